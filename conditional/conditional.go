@@ -0,0 +1,206 @@
+/*
+Package conditional handles the conditional requests of RFC 9110: those
+with If-Match, If-None-Match, If-Modified-Since or If-Unmodified-Since
+headers, answered with 304 Not Modified or 412 Precondition Failed when the
+condition is not met.
+
+The preconditions are evaluated in the order RFC 9110 gives:
+
+	1. If-Match, or if it is absent, If-Unmodified-Since: 412 if false
+	2. If-None-Match, or if it is absent, If-Modified-Since for GET and
+	   HEAD: 304 for GET and HEAD, else 412 if false
+
+If-Range is left to range requests; see IfRange.
+*/
+package conditional
+
+import (
+	"bytes"
+	"github.com/TShadwell/fweight"
+	"net/http"
+	"strings"
+	"time"
+)
+
+/*
+Conditional is a fweight.Middleware handling conditional requests.
+
+If Validators is set, it is called with the request to get the entity tag
+and modification time of the resource before the wrapped http.Handler is
+called, so that it is not if the preconditions fail. It should return an
+empty etag and a zero time if the resource does not exist. This must be
+used for methods like PUT and DELETE, whose preconditions must be checked
+before the resource is changed.
+
+Otherwise, the responses to GET and HEAD requests are buffered, and if they
+are successful, checked against the ETag and Last-Modified headers the
+handler set. If the handler set no ETag, a 200 OK response is given that
+of its body, so a HEAD response must have the same body as a GET for its
+ETag to match, as it does when route.Verb routes HEAD as GET. Other
+requests are served as they are.
+*/
+type Conditional struct {
+	Validators func(rq *http.Request) (etag string, lastModified time.Time)
+}
+
+//Middleware handles conditional requests by buffering responses. See
+//Conditional.
+var Middleware = Conditional{}
+
+func (c Conditional) Middleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, rq *http.Request) {
+		if c.Validators != nil {
+			c.serveValidated(h, rw, rq)
+			return
+		}
+
+		if rq.Method != "GET" && rq.Method != "HEAD" {
+			h.ServeHTTP(rw, rq)
+			return
+		}
+
+		b := &buffer{ResponseWriter: rw}
+		h.ServeHTTP(b, rq)
+		b.flush(rq)
+	})
+}
+
+func (c Conditional) serveValidated(h http.Handler, rw http.ResponseWriter, rq *http.Request) {
+	etag, lastModified := c.Validators(rq)
+	exists := etag != "" || !lastModified.IsZero()
+
+	if rq.Method == "GET" || rq.Method == "HEAD" {
+		setValidators(rw.Header(), etag, lastModified)
+	}
+	if status := evaluate(rq, etag, lastModified, exists); status != 0 {
+		fail(rw, status)
+		return
+	}
+	h.ServeHTTP(rw, rq)
+}
+
+//Function Evaluate evaluates the preconditions of rq against a
+//representation with the entity tag etag and modification time
+//lastModified, either of which may be unset. It returns 0 if rq should be
+//served, or else the status to answer it with: 304 Not Modified or
+//412 Precondition Failed.
+func Evaluate(rq *http.Request, etag string, lastModified time.Time) fweight.Status {
+	return evaluate(rq, etag, lastModified, etag != "" || !lastModified.IsZero())
+}
+
+func evaluate(rq *http.Request, etag string, lastModified time.Time, exists bool) fweight.Status {
+	get := rq.Method == "GET" || rq.Method == "HEAD"
+
+	if im, ok := rq.Header["If-Match"]; ok {
+		if !match(strings.Join(im, ","), etag, exists, StrongMatch) {
+			return fweight.StatusPreconditionFailed
+		}
+	} else if t, ok := date(rq, "If-Unmodified-Since"); ok && !lastModified.IsZero() {
+		if lastModified.Truncate(time.Second).After(t) {
+			return fweight.StatusPreconditionFailed
+		}
+	}
+
+	if inm, ok := rq.Header["If-None-Match"]; ok {
+		if match(strings.Join(inm, ","), etag, exists, WeakMatch) {
+			if get {
+				return fweight.StatusNotModified
+			}
+			return fweight.StatusPreconditionFailed
+		}
+	} else if t, ok := date(rq, "If-Modified-Since"); ok && get && !lastModified.IsZero() {
+		if !lastModified.Truncate(time.Second).After(t) {
+			return fweight.StatusNotModified
+		}
+	}
+
+	return 0
+}
+
+//Function IfRange reports whether the Range header of rq should be honoured
+//for a representation with the entity tag etag and modification time
+//lastModified: if rq has no If-Range header, or it is an entity tag
+//matching etag by the strong comparison, or a date equal to lastModified.
+func IfRange(rq *http.Request, etag string, lastModified time.Time) bool {
+	ir := rq.Header.Get("If-Range")
+	if ir == "" {
+		return true
+	}
+	if tag, _ := scanETag(ir); tag != "" {
+		return StrongMatch(tag, etag)
+	}
+	t, err := http.ParseTime(ir)
+	return err == nil && !lastModified.IsZero() && lastModified.Truncate(time.Second).Equal(t)
+}
+
+func date(rq *http.Request, name string) (time.Time, bool) {
+	t, err := http.ParseTime(rq.Header.Get(name))
+	return t, err == nil
+}
+
+func setValidators(h http.Header, etag string, lastModified time.Time) {
+	if etag != "" {
+		h.Set("ETag", etag)
+	}
+	if !lastModified.IsZero() {
+		h.Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+}
+
+//Function fail answers a request whose preconditions failed with status.
+func fail(rw http.ResponseWriter, status fweight.Status) {
+	h := rw.Header()
+	for _, k := range []string{"Content-Type", "Content-Length", "Content-Encoding"} {
+		delete(h, k)
+	}
+	if status == fweight.StatusNotModified {
+		rw.WriteHeader(int(status))
+		return
+	}
+	h.Set("Content-Type", "text/plain; charset=utf-8")
+	rw.WriteHeader(int(status))
+	rw.Write([]byte(status.String()))
+}
+
+//buffer buffers a response so that its preconditions can be checked
+//against the validators its handler sets.
+type buffer struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (b *buffer) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+func (b *buffer) Write(p []byte) (int, error) {
+	b.WriteHeader(http.StatusOK)
+	return b.body.Write(p)
+}
+
+//Function flush writes the buffered response to rq, or 304 or 412 if its
+//preconditions fail.
+func (b *buffer) flush(rq *http.Request) {
+	b.WriteHeader(http.StatusOK)
+	h := b.ResponseWriter.Header()
+
+	if b.status >= 200 && b.status < 300 {
+		etag := h.Get("ETag")
+		if etag == "" && b.status == http.StatusOK {
+			etag = ETag(b.body.Bytes())
+			h.Set("ETag", etag)
+		}
+		lastModified, _ := http.ParseTime(h.Get("Last-Modified"))
+
+		if status := evaluate(rq, etag, lastModified, true); status != 0 {
+			fail(b.ResponseWriter, status)
+			return
+		}
+	}
+
+	b.ResponseWriter.WriteHeader(b.status)
+	b.ResponseWriter.Write(b.body.Bytes())
+}
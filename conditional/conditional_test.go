@@ -0,0 +1,125 @@
+package conditional
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var modified = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func serve(h http.Handler, method string, header map[string]string) *httptest.ResponseRecorder {
+	rw := httptest.NewRecorder()
+	rq := httptest.NewRequest(method, "http://example.com/", nil)
+	for k, v := range header {
+		rq.Header.Set(k, v)
+	}
+	h.ServeHTTP(rw, rq)
+	return rw
+}
+
+func TestMiddleware(t *testing.T) {
+	body := "hello"
+	tag := ETag([]byte(body))
+	before, after := modified.Add(-time.Hour).Format(http.TimeFormat), modified.Add(time.Hour).Format(http.TimeFormat)
+
+	h := Middleware.Middleware(http.HandlerFunc(func(rw http.ResponseWriter, rq *http.Request) {
+		rw.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+		fmt.Fprint(rw, body)
+	}))
+
+	for _, c := range []struct {
+		method string
+		header map[string]string
+		code   int
+	}{
+		{"GET", nil, 200},
+		{"GET", map[string]string{"If-None-Match": tag}, 304},
+		{"GET", map[string]string{"If-None-Match": "W/" + tag}, 304},
+		{"HEAD", map[string]string{"If-None-Match": `"a,b", ` + tag}, 304},
+		{"HEAD", map[string]string{"If-None-Match": `"other"`}, 200},
+		{"HEAD", map[string]string{"If-Modified-Since": after}, 304},
+		{"GET", map[string]string{"If-None-Match": `"other"`}, 200},
+		{"GET", map[string]string{"If-None-Match": "*"}, 304},
+		{"GET", map[string]string{"If-Match": tag}, 200},
+		{"GET", map[string]string{"If-Match": "W/" + tag}, 412},
+		{"GET", map[string]string{"If-Match": `"other"`}, 412},
+		{"GET", map[string]string{"If-Modified-Since": after}, 304},
+		{"GET", map[string]string{"If-Modified-Since": before}, 200},
+		//If-None-Match takes precedence over If-Modified-Since
+		{"GET", map[string]string{"If-None-Match": `"other"`, "If-Modified-Since": after}, 200},
+		{"GET", map[string]string{"If-Unmodified-Since": before}, 412},
+		{"GET", map[string]string{"If-Unmodified-Since": after}, 200},
+		//If-Match takes precedence over If-Unmodified-Since
+		{"GET", map[string]string{"If-Match": tag, "If-Unmodified-Since": before}, 200},
+		//and 412 over 304
+		{"GET", map[string]string{"If-Match": `"other"`, "If-None-Match": tag}, 412},
+		//other methods are not buffered
+		{"POST", map[string]string{"If-None-Match": tag}, 200},
+	} {
+		rw := serve(h, c.method, c.header)
+		if rw.Code != c.code {
+			t.Errorf("%s %v: expected %d, got %d", c.method, c.header, c.code, rw.Code)
+		}
+		if c.code == 200 && c.method != "POST" && (rw.Header().Get("ETag") != tag || rw.Body.String() != body) {
+			t.Errorf("%s %v: got %v %q", c.method, c.header, rw.Header(), rw.Body)
+		}
+		if c.code == 304 && (rw.Body.Len() != 0 || rw.Header().Get("ETag") != tag) {
+			t.Errorf("%s %v: got %v %q", c.method, c.header, rw.Header(), rw.Body)
+		}
+	}
+}
+
+func TestValidators(t *testing.T) {
+	var current string
+	served := false
+	h := Conditional{
+		Validators: func(*http.Request) (string, time.Time) {
+			return current, time.Time{}
+		},
+	}.Middleware(http.HandlerFunc(func(rw http.ResponseWriter, rq *http.Request) {
+		served = true
+	}))
+
+	for _, c := range []struct {
+		current string
+		method  string
+		header  map[string]string
+		code    int
+	}{
+		{"", "PUT", map[string]string{"If-None-Match": "*"}, 200},
+		{`"1"`, "PUT", map[string]string{"If-None-Match": "*"}, 412},
+		{`"1"`, "PUT", map[string]string{"If-Match": `"1"`}, 200},
+		{`"2"`, "PUT", map[string]string{"If-Match": `"1"`}, 412},
+		{"", "DELETE", map[string]string{"If-Match": "*"}, 412},
+		{`"2"`, "GET", map[string]string{"If-None-Match": `"2"`}, 304},
+	} {
+		current, served = c.current, false
+		rw := serve(h, c.method, c.header)
+		if rw.Code != c.code || served != (c.code == 200) {
+			t.Errorf("%s %s %v: expected %d, got %d (served %v)", c.current, c.method, c.header, c.code, rw.Code, served)
+		}
+	}
+}
+
+func TestIfRange(t *testing.T) {
+	tag := `"1"`
+	for header, expected := range map[string]bool{
+		"":                               true,
+		tag:                              true,
+		"W/" + tag:                       false,
+		`"2"`:                            false,
+		modified.Format(http.TimeFormat): true,
+		modified.Add(time.Hour).Format(http.TimeFormat): false,
+	} {
+		rq := httptest.NewRequest("GET", "http://example.com/", nil)
+		if header != "" {
+			rq.Header.Set("If-Range", header)
+		}
+		if got := IfRange(rq, tag, modified); got != expected {
+			t.Errorf("If-Range %q: expected %v, got %v", header, expected, got)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package conditional
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+//Function ETag returns a strong entity tag for the representation body, a
+//hash of it.
+func ETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:12]) + `"`
+}
+
+//Function Weak reports whether the entity tag etag is weak (W/"...").
+func Weak(etag string) bool {
+	return strings.HasPrefix(etag, "W/")
+}
+
+//Function StrongMatch reports whether the entity tags a and b match by the
+//strong comparison of RFC 9110: both are strong, and they are the same.
+func StrongMatch(a, b string) bool {
+	return a != "" && a == b && !Weak(a)
+}
+
+//Function WeakMatch reports whether the entity tags a and b match by the
+//weak comparison of RFC 9110: they are the same, weak or not.
+func WeakMatch(a, b string) bool {
+	a, b = strings.TrimPrefix(a, "W/"), strings.TrimPrefix(b, "W/")
+	return a != "" && a == b
+}
+
+//Function match reports whether the If-Match or If-None-Match header value
+//list matches the entity tag etag of a representation by the comparison
+//match, "*" matching any representation that exists.
+func match(list, etag string, exists bool, match func(a, b string) bool) bool {
+	if strings.TrimSpace(list) == "*" {
+		return exists
+	}
+	for {
+		var tag string
+		if tag, list = scanETag(list); tag == "" {
+			return false
+		}
+		if match(tag, etag) {
+			return true
+		}
+	}
+}
+
+//Function scanETag returns the first entity tag of the list s, and the
+//rest of it, or an empty tag if there is none. Entity tags may contain
+//commas, so the list cannot simply be split.
+func scanETag(s string) (etag, rest string) {
+	s = strings.TrimLeft(s, " \t,")
+	start := 0
+	if strings.HasPrefix(s, "W/") {
+		start = 2
+	}
+	if len(s) <= start || s[start] != '"' {
+		return "", ""
+	}
+	end := strings.IndexByte(s[start+1:], '"')
+	if end < 0 {
+		return "", ""
+	}
+	end += start + 2
+	return s[:end], s[end:]
+}
@@ -4,11 +4,15 @@ import (
 	"net/http"
 )
 
+//Type Status is an HTTP status code. The constants are those of the IANA
+//HTTP Status Code Registry, and 418.
 type Status uint16
 
 const (
 	StatusContinue           Status = 100
 	StatusSwitchingProtocols Status = 101
+	StatusProcessing         Status = 102
+	StatusEarlyHints         Status = 103
 
 	StatusOK                   Status = 200
 	StatusCreated              Status = 201
@@ -17,6 +21,9 @@ const (
 	StatusNoContent            Status = 204
 	StatusResetContent         Status = 205
 	StatusPartialContent       Status = 206
+	StatusMultiStatus          Status = 207
+	StatusAlreadyReported      Status = 208
+	StatusIMUsed               Status = 226
 
 	StatusMultipleChoices   Status = 300
 	StatusMovedPermanently  Status = 301
@@ -25,6 +32,7 @@ const (
 	StatusNotModified       Status = 304
 	StatusUseProxy          Status = 305
 	StatusTemporaryRedirect Status = 307
+	StatusPermanentRedirect Status = 308
 
 	StatusBadRequest                   Status = 400
 	StatusUnauthorized                 Status = 401
@@ -45,13 +53,36 @@ const (
 	StatusRequestedRangeNotSatisfiable Status = 416
 	StatusExpectationFailed            Status = 417
 	StatusTeapot                       Status = 418
+	StatusMisdirectedRequest           Status = 421
+	StatusUnprocessableEntity          Status = 422
+	StatusLocked                       Status = 423
+	StatusFailedDependency             Status = 424
+	StatusTooEarly                     Status = 425
+	StatusUpgradeRequired              Status = 426
+	StatusPreconditionRequired         Status = 428
+	StatusTooManyRequests              Status = 429
+	StatusRequestHeaderFieldsTooLarge  Status = 431
+	StatusUnavailableForLegalReasons   Status = 451
 
-	StatusInternalServerError     Status = 500
-	StatusNotImplemented          Status = 501
-	StatusBadGateway              Status = 502
-	StatusServiceUnavailable      Status = 503
-	StatusGatewayTimeout          Status = 504
-	StatusHTTPVersionNotSupported Status = 505
+	StatusInternalServerError           Status = 500
+	StatusNotImplemented                Status = 501
+	StatusBadGateway                    Status = 502
+	StatusServiceUnavailable            Status = 503
+	StatusGatewayTimeout                Status = 504
+	StatusHTTPVersionNotSupported       Status = 505
+	StatusVariantAlsoNegotiates         Status = 506
+	StatusInsufficientStorage           Status = 507
+	StatusLoopDetected                  Status = 508
+	StatusNotExtended                   Status = 510
+	StatusNetworkAuthenticationRequired Status = 511
+)
+
+//The names RFC 9110 gives statuses renamed since RFC 2616.
+const (
+	StatusContentTooLarge      = StatusRequestEntityTooLarge
+	StatusURITooLong           = StatusRequestURITooLong
+	StatusRangeNotSatisfiable  = StatusRequestedRangeNotSatisfiable
+	StatusUnprocessableContent = StatusUnprocessableEntity
 )
 
 //Function String returns the status text associated with this Status
@@ -64,22 +95,40 @@ func (s Status) HTTPStatusCode() Status {
 	return s
 }
 
+//Function IsRedirect reports whether s is a 3xx status.
+func (s Status) IsRedirect() bool {
+	return s >= 300 && s < 400
+}
+
+//Function IsClientError reports whether s is a 4xx status.
+func (s Status) IsClientError() bool {
+	return s >= 400 && s < 500
+}
+
+//Function IsServerError reports whether s is a 5xx status.
+func (s Status) IsServerError() bool {
+	return s >= 500 && s < 600
+}
+
 type HTTPStatus interface {
 	HTTPStatusCode() Status
 }
 
 /*
 	Returns an Err as the concrete value
-	of an error interface, or nil.
+	of an error interface, or nil if s
+	is not an error status (400 or above).
 */
 func (s Status) Err() error {
-	if s > 400 {
+	if s >= 400 {
 		return Err(s)
 	}
 	return nil
 }
 
-//Type Err represents an HTTP error code (Status > 400).
+//Type Err represents an HTTP error code (Status >= 400). A Handler in a
+//RouteHandler can panic with one, or an object Getter return one, to have
+//the response handled by the RouteHandler's StatusHandlers.
 type Err Status
 
 func (e Err) HTTPStatusCode() Status {
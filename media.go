@@ -0,0 +1,123 @@
+package fweight
+
+import (
+	"log"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type MediaType string
+
+type ContentType struct {
+	MediaType
+	Params map[string]string
+}
+
+//Function ParseContentType returns an array of ContentTypes corresponding to the
+//Content-Type header string cts.
+func ParseContentType(cts string) (c []ContentType, err error) {
+	if cts == "" {
+		return
+	}
+
+	ctts := strings.Split(cts, ",")
+	c = make([]ContentType, len(ctts))
+	for i, v := range ctts {
+		c[i].MediaType, c[i].Params = ParseMediaType(v)
+	}
+	return
+}
+
+//Function ParseMediaType parses a single media type and its parameters,
+//as mime.ParseMediaType does, ignoring errors.
+func ParseMediaType(v string) (mt MediaType, p map[string]string) {
+	var s string
+	var err error
+	s, p, err = mime.ParseMediaType(v)
+	if debug && err != nil {
+		panic(err)
+	}
+	mt = MediaType(s)
+	if debug {
+		log.Println(mt, "---", p, "---", v, "---")
+	}
+	return
+}
+
+//Function Q returns the quality value ("q") of this ContentType in
+//an Accept header, which is 1 if none is given.
+func (c ContentType) Q() float64 {
+	q, ok := c.Params["q"]
+	if !ok {
+		return 1
+	}
+	f, err := strconv.ParseFloat(q, 64)
+	if err != nil || f < 0 {
+		return 0
+	}
+	if f > 1 {
+		return 1
+	}
+	return f
+}
+
+//Function ParseAccept returns the media ranges of the Accept header
+//accept, most preferred first. Ranges that could not be parsed are left out.
+func ParseAccept(accept string) []ContentType {
+	cts, _ := ParseContentType(accept)
+	ranges := cts[:0]
+	for _, ct := range cts {
+		if ct.MediaType != "" {
+			ranges = append(ranges, ct)
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].Q() > ranges[j].Q()
+	})
+	return ranges
+}
+
+//Function Specificity returns how specific this media range is: 3 for
+//a media type ("text/html"), 2 for a range of subtypes ("text/*"), 1 for
+//any type ("*/*") and 0 for one that is not valid.
+func (m MediaType) Specificity() int {
+	typ, sub, ok := strings.Cut(string(m), "/")
+	switch {
+	case !ok:
+		return 0
+	case typ == "*":
+		return 1
+	case sub == "*":
+		return 2
+	}
+	return 3
+}
+
+//Function Matches reports whether the media type m is in the
+//media range r.
+func (m MediaType) Matches(r MediaType) bool {
+	switch r.Specificity() {
+	case 1:
+		return true
+	case 2:
+		return strings.HasPrefix(string(m), string(r[:len(r)-1]))
+	case 3:
+		return m == r
+	}
+	return false
+}
+
+//Function Quality returns the quality with which the media type m is
+//accepted by the media ranges of an Accept header, as returned by
+//ParseAccept. The most specific range that matches gives the quality.
+func Quality(m MediaType, ranges []ContentType) (q float64) {
+	specificity := 0
+	for _, r := range ranges {
+		if s := r.Specificity(); s > specificity && m.Matches(r.MediaType) {
+			q, specificity = r.Q(), s
+		}
+	}
+	return
+}
@@ -6,7 +6,8 @@ import (
 )
 
 //Functin Modified is a more general implement of net/http's ServeContent.
-//Modifed handles If-Modified-Since requests. ETag, Content-Range and the rest can be handled by a Middleware.  A zero lastModified
+//Modifed handles If-Modified-Since requests. ETags and the other preconditions are handled by the Middleware
+//of the conditional package, and ranges by that of the ranges package. A zero lastModified
 //is assumed to mean not modified.
 func Modified(w interface {
 	Header() http.Header
@@ -1,6 +1,7 @@
 package object
 
 import (
+	"github.com/TShadwell/fweight"
 	"github.com/TShadwell/fweight/route"
 	"log"
 	"net/http"
@@ -74,7 +75,27 @@ type Handler struct {
 }
 
 //Serveobject serves an interface 'o' using the handler. If o is empty, the response will be empty.
+//
+//If o implements fweight.HTTPStatus, the response has its status. If that is
+//an error status, such as that of a fweight.Err, an Error or a
+//*fweight.Problem, ServeObject panics with o so that the RouteHandler serving
+//rq handles it by its StatusHandlers, as it would a Handler's panic. A bare
+//fweight.Status is served without a body.
 func (h Handler) ServeObject(o interface{}, rw http.ResponseWriter, rq *http.Request) {
+	if hs, ok := o.(fweight.HTTPStatus); ok {
+		status := hs.HTTPStatusCode()
+		if status.Err() != nil {
+			panic(o)
+		}
+		if _, ok := o.(fweight.Status); ok {
+			rw.WriteHeader(int(status))
+			return
+		}
+		sw := &statusWriter{ResponseWriter: rw, status: int(status)}
+		defer sw.WriteHeader(sw.status)
+		rw = sw
+	}
+
 	var ms []ContentMarshaler
 	if h.Archetype != nil {
 		ms = []ContentMarshaler{
@@ -132,11 +153,11 @@ func (h Handler) ServeObject(o interface{}, rw http.ResponseWriter, rq *http.Req
 }
 
 /*
-Function BindFweight binds this Archetype to Fweight's OptionsHandler
-and MethodNotAllowed handlers.
+Function Bind binds this Archetype to the Options and MethodNotAllowed
+handlers of the RouteHandler r, which serve the list of allowed methods.
 */
-func (a *Archetype) Bind() {
+func (a *Archetype) Bind(r *route.RouteHandler) {
 	hnd := a.Handler()
-	route.OptionsHandler = hnd.ServeObject
-	route.MethodNotAllowed = hnd.ServeObject
+	r.Options = hnd.ServeObject
+	r.MethodNotAllowed = hnd.ServeObject
 }
@@ -5,6 +5,8 @@ import (
 	"encoding/gob"
 	"encoding/json"
 	"encoding/xml"
+	"github.com/TShadwell/fweight"
+	"github.com/TShadwell/fweight/route"
 	"github.com/TShadwell/jsarray"
 	htmltemplate "html/template"
 	"io"
@@ -62,6 +64,18 @@ func TextTemplate(t *texttemplate.Template) MarshalFunc {
 	}
 }
 
+//Function FuncMap returns functions for the templates executed by HTMLTemplate
+//and TextTemplate. "url" builds a URL to a route.Named in the tree root:
+//
+//	<a href="{{url "user" "id" .ID}}">
+//
+//See route.URL.
+func FuncMap(root route.Router) texttemplate.FuncMap {
+	return texttemplate.FuncMap{
+		"url": route.URLFunc(root),
+	}
+}
+
 var Json MarshalFunc = func(r Responder, rq Request) error {
 	r.ContentType("application/json;charset=utf8")
 
@@ -74,6 +88,46 @@ var Xml MarshalFunc = func(r Responder, rq Request) error {
 	return xml.NewEncoder(r).Encode(r.I)
 }
 
+//ProblemJson marshals RFC 9457 problems as application/problem+json. Values
+//that are not *fweight.Problems but implement fweight.HTTPStatus, such as
+//Errors, are marshaled as the problem of their status.
+var ProblemJson MarshalFunc = func(r Responder, rq Request) error {
+	r.ContentType("application/problem+json;charset=utf8")
+
+	return json.NewEncoder(r).Encode(problem(r.I))
+}
+
+//ProblemXml is ProblemJson for application/problem+xml.
+var ProblemXml MarshalFunc = func(r Responder, rq Request) error {
+	r.ContentType("application/problem+xml;charset=utf8")
+
+	return xml.NewEncoder(r).Encode(problem(r.I))
+}
+
+//ProblemMarshaler marshals RFC 9457 problems. See ProblemJson.
+var ProblemMarshaler = ContentMarshaler{
+	"application/problem+json": ProblemJson,
+	"application/problem+xml":  ProblemXml,
+}
+
+//Function problem returns i as a *fweight.Problem, if it is a problem or
+//an HTTPStatus, or else i.
+func problem(i interface{}) interface{} {
+	switch v := i.(type) {
+	case *fweight.Problem:
+		return v
+	case fweight.Problem:
+		return &v
+	case Error:
+		return v.Problem()
+	case *Error:
+		return v.Problem()
+	case fweight.HTTPStatus:
+		return &fweight.Problem{Status: v.HTTPStatusCode()}
+	}
+	return i
+}
+
 var nullbytes = []byte("null")
 
 //See github.com/TShadwell/jsarray for details.
@@ -195,7 +249,7 @@ func RequestMarshaler(r *http.Request, cs ...ContentMarshaler) (mf MarshalFunc,
 	}
 	if m := pathMime(r.URL.Path); m != "" {
 		a, b := pmt(m)
-		types = append([]ContentType{{a, b}}, types...)
+		types = append([]ContentType{{MediaType: a, Params: b}}, types...)
 		if debug {
 			log.Printf("%+v", types)
 		}
@@ -0,0 +1,40 @@
+package object
+
+import (
+	"errors"
+	"github.com/TShadwell/fweight/route"
+	htmltemplate "html/template"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFuncMap(t *testing.T) {
+	root := route.Path{
+		"users": route.Path{
+			"&id": route.Name("user", route.HandleFunc(nil)),
+		},
+	}
+
+	for _, c := range []struct {
+		template string
+		expected string
+		missing  string
+	}{
+		{`<a href="{{url "user" "id" .}}">`, `<a href="/users/bob%20smith">`, ""},
+		{`<a href="{{url "user"}}">`, "", "id"},
+	} {
+		mf := HTMLTemplate(htmltemplate.Must(htmltemplate.New("").Funcs(htmltemplate.FuncMap(FuncMap(root))).Parse(c.template)))
+
+		rw := httptest.NewRecorder()
+		rq := httptest.NewRequest("GET", "http://example.com/", nil)
+		err := mf(Responder{I: "bob smith", ResponseWriter: rw}, Request{Request: rq})
+
+		var missing route.ErrMissingParam
+		switch {
+		case c.missing == "" && (err != nil || rw.Body.String() != c.expected):
+			t.Errorf("%s: expected %q, got %q, %v", c.template, c.expected, rw.Body.String(), err)
+		case c.missing != "" && (!errors.As(err, &missing) || missing.Param != c.missing):
+			t.Errorf("%s: expected missing %q, got %v", c.template, c.missing, err)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package object
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/TShadwell/fweight"
+	"github.com/TShadwell/fweight/route"
+	htmltemplate "html/template"
+	"log"
+	"net/http"
+	deb "runtime/debug"
+	"sort"
+)
+
+//An Error is the body of an error response. A Handler can panic with an
+//Error to have its Detail shown.
+type Error struct {
+	XMLName   xml.Name       `json:"-" xml:"error"`
+	Status    fweight.Status `json:"status" xml:"status"`
+	Title     string         `json:"title" xml:"title"`
+	Detail    string         `json:"detail,omitempty" xml:"detail,omitempty"`
+	RequestID string         `json:"requestId,omitempty" xml:"requestId,omitempty"`
+	//Request is only set by ErrorPages with Debug set.
+	Request *RequestDump `json:"request,omitempty" xml:"request,omitempty"`
+}
+
+func (e Error) HTTPStatusCode() fweight.Status {
+	return e.Status
+}
+
+func (e Error) Error() string {
+	if e.Detail == "" {
+		return e.Title
+	}
+	return e.Title + ": " + e.Detail
+}
+
+//Function Problem returns e as an RFC 9457 problem, its RequestID and
+//Request being extension members.
+func (e Error) Problem() *fweight.Problem {
+	p := &fweight.Problem{
+		Status: e.Status,
+		Title:  e.Title,
+		Detail: e.Detail,
+	}
+	if e.RequestID != "" {
+		p.With("requestId", e.RequestID)
+	}
+	if e.Request != nil {
+		p.With("request", e.Request)
+	}
+	return p
+}
+
+//A RequestDump describes the request an Error is for, for debugging.
+type RequestDump struct {
+	Method     string        `json:"method" xml:"method"`
+	URI        string        `json:"uri" xml:"uri"`
+	Proto      string        `json:"proto" xml:"proto"`
+	Header     []HeaderField `json:"header" xml:"header"`
+	RemoteAddr string        `json:"remoteAddr" xml:"remoteAddr"`
+}
+
+//A HeaderField is a header of a RequestDump.
+type HeaderField struct {
+	Name  string `json:"name" xml:"name,attr"`
+	Value string `json:"value" xml:",chardata"`
+}
+
+func dump(rq *http.Request) *RequestDump {
+	d := &RequestDump{
+		Method:     rq.Method,
+		URI:        rq.Host + rq.URL.RequestURI(),
+		Proto:      rq.Proto,
+		RemoteAddr: rq.RemoteAddr,
+	}
+	names := make([]string, 0, len(rq.Header))
+	for k := range rq.Header {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		for _, v := range rq.Header[k] {
+			d.Header = append(d.Header, HeaderField{k, v})
+		}
+	}
+	return d
+}
+
+//ErrorPage is the template of the HTML error pages of DefaultErrorMarshaler.
+var ErrorPage = htmltemplate.Must(htmltemplate.New("error").Parse(`<!DOCTYPE html>
+<html>
+<head><title>{{printf "%d" .Status}} {{.Title}}</title></head>
+<body>
+<h1>{{.Title}}</h1>
+{{with .Detail}}<p>{{.}}</p>
+{{end}}{{with .RequestID}}<p>Request ID: <code>{{.}}</code></p>
+{{end}}{{with .Request}}<h2>Request</h2>
+<p><code>{{.Method}} {{.URI}} {{.Proto}}</code> from <code>{{.RemoteAddr}}</code></p>
+<table>
+{{range .Header}}<tr><th>{{.Name}}</th><td>{{.Value}}</td></tr>
+{{end}}</table>
+{{end}}</body>
+</html>
+`))
+
+//DefaultErrorMarshaler renders Errors as HTML pages for browsers.
+var DefaultErrorMarshaler = ContentMarshaler{
+	"text/html": HTMLTemplate(ErrorPage),
+}
+
+/*
+ErrorPages serves error responses by content negotiation, as an Error
+marshaled by the Marshaler, or if none matches the request, by the
+ProblemMarshaler or the ContentMarshaler of the Archetype: browsers get an
+HTML page and API clients problem+json, JSON or XML, say.
+
+A Handler can panic with a *fweight.Problem to give API clients its members
+as they are, extensions included. The Marshaler is given it as an Error.
+
+The request ID of an Error is that given by RequestID, or the X-Request-Id
+header of the request. If Debug is set, Errors also carry the detail of
+any panic and a dump of the request, headers and cookies included, which
+should only be done in development.
+*/
+type ErrorPages struct {
+	*Archetype
+	//DefaultErrorMarshaler is used if Marshaler is nil.
+	Marshaler ContentMarshaler
+	RequestID func(*http.Request) string
+	Debug     bool
+}
+
+//Function Error returns the Error for a response of status to rq. If the
+//Handler rq was routed to panicked with an Error or a *fweight.Problem,
+//that is used.
+func (e ErrorPages) Error(status fweight.Status, rq *http.Request) Error {
+	var err Error
+	switch v := route.Recovered(rq).(type) {
+	case Error:
+		err = v
+	case *Error:
+		err = *v
+	case *fweight.Problem:
+		err = Error{
+			Status: v.Status,
+			Title:  v.Title,
+			Detail: v.Detail,
+		}
+	case nil:
+	default:
+		if e.Debug {
+			err.Detail = fmt.Sprint(v)
+		}
+	}
+
+	if err.Status == 0 {
+		err.Status = status
+	}
+	if err.Title == "" {
+		err.Title = err.Status.String()
+	}
+	if err.RequestID == "" {
+		if e.RequestID != nil {
+			err.RequestID = e.RequestID(rq)
+		} else {
+			err.RequestID = rq.Header.Get("X-Request-Id")
+		}
+	}
+	if e.Debug && err.Request == nil {
+		err.Request = dump(rq)
+	}
+	return err
+}
+
+//Function Handler returns a http.Handler serving the Error for status.
+func (e ErrorPages) Handler(status fweight.Status) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, rq *http.Request) {
+		err := e.Error(status, rq)
+
+		m := e.Marshaler
+		if m == nil {
+			m = DefaultErrorMarshaler
+		}
+
+		var o interface{} = err
+		if p, ok := route.Recovered(rq).(*fweight.Problem); ok {
+			o, m = p, marshalAs(m, err)
+		}
+
+		ms := []ContentMarshaler{m, ProblemMarshaler}
+		if e.Archetype != nil {
+			ms = append(ms, e.Archetype.ContentMarshaler)
+		}
+
+		mf, ct := RequestMarshaler(rq, ms...)
+		if mf == nil {
+			for _, c := range ms {
+				if mf = c[""]; mf != nil {
+					break
+				}
+			}
+		}
+		if mf == nil {
+			mf, o = Plain, err.Error()
+		}
+
+		sw := &statusWriter{ResponseWriter: rw, status: int(err.Status)}
+		defer sw.WriteHeader(sw.status)
+		if merr := mf(
+			Responder{
+				I:              o,
+				ResponseWriter: sw,
+			},
+			Request{
+				Request:   rq,
+				Params:    ct.Params,
+				MediaType: ct.MediaType,
+			},
+		); merr != nil {
+			log.Printf("[!] Error marshaling error response %+v: %s", err, merr)
+		}
+	})
+}
+
+/*
+Function Bind sets the NotFound, Recover, NotAcceptable and StatusHandlers
+of the RouteHandler r to serve ErrorPages, for every 4xx and 5xx status
+with a status text. Panics that are not HTTPStatus values are logged with
+their stack, as route.HandleRecovery does.
+*/
+func (e ErrorPages) Bind(r *route.RouteHandler) {
+	r.NotFound = e.Handler(fweight.StatusNotFound)
+	r.NotAcceptable = e.Handler(fweight.StatusNotAcceptable)
+	r.Recover = route.RecoverHandlerFunc(func(i interface{}) http.Handler {
+		log.Printf("Internal Server Error: %+q\n %s", fmt.Sprint(i), deb.Stack())
+		return e.Handler(fweight.StatusInternalServerError)
+	})
+
+	if r.StatusHandlers == nil {
+		r.StatusHandlers = make(map[fweight.Status]http.Handler)
+	}
+	for s := fweight.Status(400); s < 600; s++ {
+		if http.StatusText(int(s)) != "" {
+			r.StatusHandlers[s] = e.Handler(s)
+		}
+	}
+}
+
+//Function marshalAs returns m, marshaling o whatever it is given.
+func marshalAs(m ContentMarshaler, o interface{}) ContentMarshaler {
+	c := make(ContentMarshaler, len(m))
+	for k, mf := range m {
+		mf := mf
+		c[k] = func(r Responder, rq Request) error {
+			r.I = o
+			return mf(r, rq)
+		}
+	}
+	return c
+}
+
+//statusWriter writes status before the body, once the MarshalFunc has set
+//the Content-Type.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	wrote  bool
+}
+
+func (s *statusWriter) WriteHeader(status int) {
+	if !s.wrote {
+		s.wrote = true
+		s.ResponseWriter.WriteHeader(status)
+	}
+}
+
+func (s *statusWriter) Write(p []byte) (int, error) {
+	s.WriteHeader(s.status)
+	return s.ResponseWriter.Write(p)
+}
@@ -0,0 +1,136 @@
+package object
+
+import (
+	"errors"
+	"github.com/TShadwell/fweight"
+	"github.com/TShadwell/fweight/route"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var invalid = &fweight.Problem{
+	Status:     fweight.StatusUnprocessableEntity,
+	Type:       "https://example.com/invalid",
+	Title:      "Invalid",
+	Detail:     "name is empty",
+	Extensions: map[string]interface{}{"field": "name"},
+	Cause:      errors.New("cause"),
+	Header:     http.Header{"Retry-After": {"30"}},
+}
+
+func TestErrorPages(t *testing.T) {
+	for _, c := range []struct {
+		debug       bool
+		path        string
+		accept      string
+		code        int
+		contentType string
+		contains    []string
+		excludes    []string
+	}{
+		{false, "/nowhere", "text/html", 404, "text/html", []string{"<title>404 Not Found</title>", "<h1>Not Found</h1>", "req-1"}, []string{"secret"}},
+		{false, "/nowhere", "application/json", 404, "application/json", []string{`"status":404`, `"requestId":"req-1"`}, []string{"secret"}},
+		{false, "/nowhere", "application/xml", 404, "application/xml", []string{"<status>404</status>"}, []string{"secret"}},
+		{false, "/taken", "application/json", 409, "application/json", []string{`"detail":"name taken"`}, nil},
+		{false, "/broken", "application/json", 500, "application/json", []string{`"title":"Internal Server Error"`}, []string{"oops", "secret"}},
+		{true, "/broken", "application/json", 500, "application/json", []string{`"detail":"oops"`, "secret"}, nil},
+		{true, "/nowhere", "text/html", 404, "text/html", []string{"Cookie", "secret"}, nil},
+		{false, "/nowhere", "application/problem+json", 404, "application/problem+json", []string{`"status":404`, `"requestId":"req-1"`}, nil},
+		{false, "/invalid", "application/problem+json", 422, "application/problem+json", []string{`"type":"https://example.com/invalid"`, `"field":"name"`, `"detail":"name is empty"`}, []string{"cause"}},
+		{false, "/invalid", "application/problem+xml", 422, "application/problem+xml", []string{`<problem xmlns="urn:ietf:rfc:7807">`, "<field>name</field>"}, nil},
+		{false, "/invalid", "application/json", 422, "application/json", []string{`"field":"name"`}, nil},
+		{false, "/invalid", "text/html", 422, "text/html", []string{"<title>422 Invalid</title>", "<p>name is empty</p>"}, []string{"field"}},
+	} {
+		h := route.RouteHandler{
+			Router: route.Path{
+				"taken": route.HandleFunc(func(rw http.ResponseWriter, rq *http.Request) {
+					panic(Error{Status: fweight.StatusConflict, Detail: "name taken"})
+				}),
+				"broken": route.HandleFunc(func(rw http.ResponseWriter, rq *http.Request) {
+					panic("oops")
+				}),
+				"invalid": route.HandleFunc(func(rw http.ResponseWriter, rq *http.Request) {
+					panic(invalid)
+				}),
+			},
+		}
+		ErrorPages{Archetype: &DefaultArchetype, Debug: c.debug}.Bind(&h)
+
+		rw := httptest.NewRecorder()
+		rq := httptest.NewRequest("GET", "http://example.com"+c.path, nil)
+		rq.Header.Set("Accept", c.accept)
+		rq.Header.Set("X-Request-Id", "req-1")
+		rq.Header.Set("Cookie", "session=secret")
+		h.ServeHTTP(rw, rq)
+
+		if c.path == "/invalid" && rw.Header().Get("Retry-After") != "30" {
+			t.Errorf("%+v: no Retry-After in %v", c, rw.Header())
+		}
+
+		body := rw.Body.String()
+		if rw.Code != c.code || !strings.HasPrefix(rw.Header().Get("Content-Type"), c.contentType) {
+			t.Errorf("%+v: got %d %s", c, rw.Code, rw.Header().Get("Content-Type"))
+		}
+		for _, s := range c.contains {
+			if !strings.Contains(body, s) {
+				t.Errorf("%+v: %q not in %s", c, s, body)
+			}
+		}
+		for _, s := range c.excludes {
+			if strings.Contains(body, s) {
+				t.Errorf("%+v: %q in %s", c, s, body)
+			}
+		}
+	}
+}
+
+func TestProblemErrors(t *testing.T) {
+	var p *fweight.Problem
+	if !errors.As(error(invalid), &p) || !errors.Is(invalid, fweight.Err(fweight.StatusUnprocessableEntity)) || errors.Unwrap(invalid) != invalid.Cause {
+		t.Errorf("%v does not unwrap", invalid)
+	}
+	if errors.Is(invalid, fweight.Err(fweight.StatusNotFound)) {
+		t.Errorf("%v is %v", invalid, fweight.Err(fweight.StatusNotFound))
+	}
+}
+
+type created struct {
+	Name string `json:"name"`
+}
+
+func (created) HTTPStatusCode() fweight.Status {
+	return fweight.StatusCreated
+}
+
+func TestServeObjectStatus(t *testing.T) {
+	for _, c := range []struct {
+		o    interface{}
+		code int
+		body string
+	}{
+		{created{"x"}, 201, `{"name":"x"}`},
+		{fweight.StatusNoContent, 204, ""},
+		{fweight.Err(fweight.StatusBadRequest), 400, `"title":"Bad Request"`},
+		{fweight.StatusTooManyRequests.Err(), 429, `"status":429`},
+		{invalid, 422, `"field":"name"`},
+	} {
+		o := c.o
+		h := route.RouteHandler{
+			Router: RouterFunc(func(ResponseWriter, *http.Request) interface{} {
+				return o
+			}),
+		}
+		ErrorPages{Archetype: &DefaultArchetype}.Bind(&h)
+
+		rw := httptest.NewRecorder()
+		rq := httptest.NewRequest("GET", "http://example.com/", nil)
+		rq.Header.Set("Accept", "application/json")
+		h.ServeHTTP(rw, rq)
+
+		if rw.Code != c.code || !strings.Contains(rw.Body.String(), c.body) || c.body == "" && rw.Body.Len() != 0 {
+			t.Errorf("%v: got %d %s", c.o, rw.Code, rw.Body)
+		}
+	}
+}
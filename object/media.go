@@ -1,13 +1,14 @@
 package object
 
 import (
-	"log"
+	"github.com/TShadwell/fweight"
 	"mime"
 	"path"
-	"strings"
 )
 
-type MediaType string
+//MediaType and ContentType are those of the fweight package, which
+//the route package shares.
+type MediaType = fweight.MediaType
 
 //returns the restricted pattern for path.Match
 func restricted(mediaType string) (patt string) {
@@ -32,36 +33,14 @@ func pathMime(p string) (typ string) {
 	return
 }
 
-type ContentType struct {
-	MediaType
-	Params map[string]string
-}
+type ContentType = fweight.ContentType
 
 //Function ParseContentType returns an array of ContentTypes corresponding to the
 //Content-Type header string cts.
 func ParseContentType(cts string) (c []ContentType, err error) {
-	if cts == "" {
-		return
-	}
-
-	ctts := strings.Split(cts, ",")
-	c = make([]ContentType, len(ctts))
-	for i, v := range ctts {
-		c[i].MediaType, c[i].Params = pmt(v)
-	}
-	return
+	return fweight.ParseContentType(cts)
 }
 
 func pmt(v string) (mt MediaType, p map[string]string) {
-	var s string
-	var err error
-	s, p, err = mime.ParseMediaType(v)
-	if debug && err != nil {
-		panic(err)
-	}
-	mt = MediaType(s)
-	if debug {
-		log.Println(mt, "---", p, "---", v, "---")
-	}
-	return
+	return fweight.ParseMediaType(v)
 }
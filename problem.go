@@ -0,0 +1,174 @@
+package fweight
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+//Type Problem is an error with the details of an RFC 9457 problem: a
+//Status, a Type URI identifying the kind of problem, a short Title of
+//that kind, a Detail of this occurrence and an Instance URI identifying
+//it. Extensions are further members, such as the fields that failed
+//validation.
+//
+//Cause is the error that caused the Problem, if any, returned by Unwrap
+//so that errors.Is and errors.As see it. It is not part of the problem
+//sent to clients. Header holds headers to send with the problem, such
+//as Retry-After.
+//
+//A Problem marshals to JSON and XML as in RFC 9457. If Type and Title are
+//both empty, the Title is the text of the Status, as for "about:blank".
+type Problem struct {
+	Status     Status
+	Type       string
+	Title      string
+	Detail     string
+	Instance   string
+	Extensions map[string]interface{}
+	Cause      error
+	Header     http.Header
+}
+
+//Function NewProblem returns a Problem with status and detail.
+func NewProblem(status Status, detail string) *Problem {
+	return &Problem{
+		Status: status,
+		Detail: detail,
+	}
+}
+
+//Function With sets the extension member name to value, returning p.
+func (p *Problem) With(name string, value interface{}) *Problem {
+	if p.Extensions == nil {
+		p.Extensions = make(map[string]interface{})
+	}
+	p.Extensions[name] = value
+	return p
+}
+
+//Function WithHeader adds value to the header name sent with p, returning p.
+func (p *Problem) WithHeader(name, value string) *Problem {
+	if p.Header == nil {
+		p.Header = make(http.Header)
+	}
+	p.Header.Add(name, value)
+	return p
+}
+
+func (p *Problem) HTTPStatusCode() Status {
+	return p.Status
+}
+
+func (p *Problem) Error() string {
+	o := p.title()
+	if o == "" {
+		o = p.Status.String()
+	}
+	if p.Detail != "" {
+		o += ": " + p.Detail
+	}
+	if p.Cause != nil {
+		o += ": " + p.Cause.Error()
+	}
+	return o
+}
+
+func (p *Problem) Unwrap() error {
+	return p.Cause
+}
+
+//Function Is reports whether target is the Err of the Status of p, so that
+//errors.Is(p, Err(StatusNotFound)) holds for Problems of 404 Not Found.
+func (p *Problem) Is(target error) bool {
+	e, ok := target.(Err)
+	return ok && Status(e) == p.Status
+}
+
+func (p *Problem) title() string {
+	if p.Title == "" && p.Type == "" {
+		return p.Status.String()
+	}
+	return p.Title
+}
+
+//Function members returns the members of p, extensions first so that
+//they cannot replace the members RFC 9457 defines.
+func (p *Problem) members() map[string]interface{} {
+	m := make(map[string]interface{}, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		m[k] = v
+	}
+	for k, v := range map[string]string{
+		"type":     p.Type,
+		"title":    p.title(),
+		"detail":   p.Detail,
+		"instance": p.Instance,
+	} {
+		delete(m, k)
+		if v != "" {
+			m[k] = v
+		}
+	}
+	delete(m, "status")
+	if p.Status != 0 {
+		m["status"] = p.Status
+	}
+	return m
+}
+
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.members())
+}
+
+//The XML namespace of problems, from RFC 9457.
+const ProblemNamespace = "urn:ietf:rfc:7807"
+
+//Function MarshalXML marshals p as in Appendix B of RFC 9457. Extensions
+//are marshaled in order of name by encoding/xml, except that slices of
+//interface{} are marshaled as elements named "i", as the RFC has it.
+func (p *Problem) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start = xml.StartElement{
+		Name: xml.Name{Space: ProblemNamespace, Local: "problem"},
+	}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	m := p.members()
+	names := make([]string, 0, len(m))
+	for k := range m {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	for _, k := range names {
+		if err := encodeMember(e, k, m[k]); err != nil {
+			return fmt.Errorf("fweight: problem member %+q: %s", k, err)
+		}
+	}
+
+	if err := e.EncodeToken(start.End()); err != nil {
+		return err
+	}
+	return e.Flush()
+}
+
+func encodeMember(e *xml.Encoder, name string, v interface{}) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	a, ok := v.([]interface{})
+	if !ok {
+		return e.EncodeElement(v, start)
+	}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, v := range a {
+		if err := encodeMember(e, "i", v); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
@@ -0,0 +1,314 @@
+/*
+Package ranges serves the byte ranges of RFC 9110 for any http.Handler,
+so that downloads of large objects and generated files can be resumed.
+
+A GET request with a Range header of satisfiable byte ranges is answered
+with 206 Partial Content: one range with a Content-Range header, several
+as a multipart/byteranges body. One with only unsatisfiable ranges is
+answered with 416 Range Not Satisfiable. If-Range is honoured against the
+ETag and Last-Modified headers of the response, as by conditional.IfRange.
+Ranges in other units, and invalid Range headers, are ignored, and the
+whole representation served.
+*/
+package ranges
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/TShadwell/fweight"
+	"github.com/TShadwell/fweight/conditional"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+)
+
+var (
+	//ErrInvalid is returned by Parse for a Range header that is not a
+	//valid set of byte ranges.
+	ErrInvalid = errors.New("ranges: invalid range")
+	//ErrUnsatisfiable is returned by Parse for a Range header with no
+	//range that overlaps the representation.
+	ErrUnsatisfiable = errors.New("ranges: no satisfiable range")
+)
+
+//A Range is a byte range of a representation.
+type Range struct {
+	Start, Length int64
+}
+
+//Function ContentRange returns the Content-Range header of r, for a
+//representation of size bytes.
+func (r Range) ContentRange(size int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d", r.Start, r.Start+r.Length-1, size)
+}
+
+/*
+Function Parse returns the satisfiable byte ranges of the Range header
+value s, for a representation of size bytes, in the order they are given.
+Ranges that extend past the end of the representation are shortened to
+end there.
+
+It returns ErrInvalid if s is not a valid set of byte ranges, or has
+none, and
+ErrUnsatisfiable if it is, but none of them overlaps the representation.
+*/
+func Parse(s string, size int64) ([]Range, error) {
+	//range units are case insensitive
+	const unit = "bytes="
+	if len(s) < len(unit) || !strings.EqualFold(s[:len(unit)], unit) {
+		return nil, ErrInvalid
+	}
+
+	var (
+		rs    []Range
+		specs int
+	)
+	for _, spec := range strings.Split(s[len(unit):], ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		specs++
+		i := strings.IndexByte(spec, '-')
+		if i < 0 {
+			return nil, ErrInvalid
+		}
+		first, last := spec[:i], spec[i+1:]
+
+		var r Range
+		if first == "" {
+			//the last n bytes
+			n, ok := digits(last)
+			if !ok {
+				return nil, ErrInvalid
+			}
+			if n == 0 || size == 0 {
+				continue
+			}
+			if n > size {
+				n = size
+			}
+			r = Range{size - n, n}
+		} else {
+			start, ok := digits(first)
+			if !ok {
+				return nil, ErrInvalid
+			}
+			end := size - 1
+			if last != "" {
+				if end, ok = digits(last); !ok || end < start {
+					return nil, ErrInvalid
+				}
+				if end >= size {
+					end = size - 1
+				}
+			}
+			if start >= size {
+				continue
+			}
+			r = Range{start, end - start + 1}
+		}
+		rs = append(rs, r)
+	}
+
+	switch {
+	case specs == 0:
+		return nil, ErrInvalid
+	case len(rs) == 0:
+		return nil, ErrUnsatisfiable
+	}
+	return rs, nil
+}
+
+//Function digits parses s, which must be only decimal digits, as
+//strconv.ParseInt would also accept a sign.
+func digits(s string) (int64, bool) {
+	if s == "" {
+		return 0, false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	return n, err == nil
+}
+
+//Function requested returns the ranges of content of size bytes requested
+//by rq, or none if it should be served whole.
+func requested(rq *http.Request, h http.Header, size int64) ([]Range, error) {
+	s := rq.Header.Get("Range")
+	if s == "" || rq.Method != "GET" {
+		return nil, nil
+	}
+	lastModified, _ := http.ParseTime(h.Get("Last-Modified"))
+	if !conditional.IfRange(rq, h.Get("ETag"), lastModified) {
+		return nil, nil
+	}
+
+	rs, err := Parse(s, size)
+	switch err {
+	case ErrInvalid:
+		return nil, nil
+	case ErrUnsatisfiable:
+		return nil, err
+	}
+
+	//more than the whole is not worth sending in parts
+	var total int64
+	for _, r := range rs {
+		total += r.Length
+	}
+	if total > size {
+		return nil, nil
+	}
+	return rs, nil
+}
+
+/*
+Function Serve serves content as the body of a successful response to rq,
+or the ranges of it that rq requests. The headers already set on rw are
+kept, and used for If-Range and the parts of multipart/byteranges bodies.
+If no Content-Type is set, it is sniffed.
+
+Serve is for handlers whose bodies are seekable. The Middleware serves
+other handlers by buffering their bodies.
+*/
+func Serve(rw http.ResponseWriter, rq *http.Request, content io.ReadSeeker) {
+	size, err := content.Seek(0, io.SeekEnd)
+	if err != nil {
+		panic(err)
+	}
+
+	h := rw.Header()
+	h.Set("Accept-Ranges", "bytes")
+	if h.Get("Content-Type") == "" {
+		h.Set("Content-Type", sniff(content))
+	}
+
+	rs, err := requested(rq, h, size)
+	if err == ErrUnsatisfiable {
+		h.Set("Content-Range", fmt.Sprintf("bytes */%d", size))
+		h.Del("Content-Length")
+		h.Set("Content-Type", "text/plain; charset=utf-8")
+		rw.WriteHeader(int(fweight.StatusRangeNotSatisfiable))
+		io.WriteString(rw, fweight.StatusRangeNotSatisfiable.String())
+		return
+	}
+
+	switch len(rs) {
+	case 0:
+		h.Set("Content-Length", strconv.FormatInt(size, 10))
+		rw.WriteHeader(http.StatusOK)
+		err = copyRange(rw, content, Range{0, size})
+	case 1:
+		h.Set("Content-Range", rs[0].ContentRange(size))
+		h.Set("Content-Length", strconv.FormatInt(rs[0].Length, 10))
+		rw.WriteHeader(http.StatusPartialContent)
+		err = copyRange(rw, content, rs[0])
+	default:
+		err = serveMultipart(rw, content, rs, size)
+	}
+	if err != nil {
+		panic(err)
+	}
+}
+
+func serveMultipart(rw http.ResponseWriter, content io.ReadSeeker, rs []Range, size int64) error {
+	h := rw.Header()
+	ct := h.Get("Content-Type")
+	mw := multipart.NewWriter(rw)
+
+	h.Set("Content-Type", "multipart/byteranges; boundary="+mw.Boundary())
+	h.Del("Content-Length")
+	rw.WriteHeader(http.StatusPartialContent)
+
+	for _, r := range rs {
+		part, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":  {ct},
+			"Content-Range": {r.ContentRange(size)},
+		})
+		if err != nil {
+			return err
+		}
+		if err := copyRange(part, content, r); err != nil {
+			return err
+		}
+	}
+	return mw.Close()
+}
+
+func copyRange(w io.Writer, content io.ReadSeeker, r Range) error {
+	if _, err := content.Seek(r.Start, io.SeekStart); err != nil {
+		return err
+	}
+	_, err := io.CopyN(w, content, r.Length)
+	return err
+}
+
+//Function sniff returns the Content-Type of content, as http.ServeContent
+//would.
+func sniff(content io.ReadSeeker) string {
+	var buf [512]byte
+	content.Seek(0, io.SeekStart)
+	n, _ := io.ReadFull(content, buf[:])
+	return http.DetectContentType(buf[:n])
+}
+
+/*
+Middleware serves the ranges of the 200 OK responses of any http.Handler to
+GET requests, by buffering them. Responses with other statuses are served
+as they are.
+
+It should wrap the Middleware of the conditional package, not be wrapped
+by it, so that preconditions are checked against whole representations:
+in a fweight.Pipeline, it comes after it.
+*/
+var Middleware = fweight.MiddlewareFunc(func(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, rq *http.Request) {
+		if rq.Method != "GET" || rq.Header.Get("Range") == "" {
+			if rq.Method == "GET" || rq.Method == "HEAD" {
+				rw.Header().Set("Accept-Ranges", "bytes")
+			}
+			h.ServeHTTP(rw, rq)
+			return
+		}
+
+		b := &buffer{ResponseWriter: rw}
+		h.ServeHTTP(b, rq)
+		b.flush(rq)
+	})
+})
+
+//buffer buffers a response so that its ranges can be served.
+type buffer struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (b *buffer) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+func (b *buffer) Write(p []byte) (int, error) {
+	b.WriteHeader(http.StatusOK)
+	return b.body.Write(p)
+}
+
+func (b *buffer) flush(rq *http.Request) {
+	b.WriteHeader(http.StatusOK)
+	if b.status == http.StatusOK {
+		Serve(b.ResponseWriter, rq, bytes.NewReader(b.body.Bytes()))
+		return
+	}
+	b.ResponseWriter.WriteHeader(b.status)
+	b.ResponseWriter.Write(b.body.Bytes())
+}
@@ -0,0 +1,124 @@
+package ranges
+
+import (
+	"fmt"
+	"github.com/TShadwell/fweight"
+	"github.com/TShadwell/fweight/conditional"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	for s, expected := range map[string]string{
+		"bytes=0-4":        "[{0 5}]",
+		"bytes=5-":         "[{5 5}]",
+		"bytes=-3":         "[{7 3}]",
+		"bytes=-30":        "[{0 10}]",
+		"bytes=8-20":       "[{8 2}]",
+		"bytes=0-0, -1":    "[{0 1} {9 1}]",
+		"bytes=10-, 20-30": "ranges: no satisfiable range",
+		"bytes=-0":         "ranges: no satisfiable range",
+		"bytes=5-4":        "ranges: invalid range",
+		"bytes=a-b":        "ranges: invalid range",
+		"items=0-4":        "ranges: invalid range",
+		"bytes=":           "ranges: invalid range",
+		"bytes=,":          "ranges: invalid range",
+		"bytes=+1-2":       "ranges: invalid range",
+		"bytes=-+2":        "ranges: invalid range",
+		"bytes=1-+2":       "ranges: invalid range",
+		"Bytes=0-4":        "[{0 5}]",
+		"BYTES=-3":         "[{7 3}]",
+	} {
+		rs, err := Parse(s, 10)
+		got := fmt.Sprint(rs)
+		if err != nil {
+			got = err.Error()
+		}
+		if got != expected {
+			t.Errorf("%s: expected %s, got %s", s, expected, got)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	const body = "0123456789"
+	modified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	h := fweight.Pipeline{
+		Base: http.HandlerFunc(func(rw http.ResponseWriter, rq *http.Request) {
+			rw.Header().Set("Content-Type", "text/plain")
+			rw.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+			fmt.Fprint(rw, body)
+		}),
+		Middleware: []fweight.Middleware{conditional.Middleware, Middleware},
+	}
+	etag := conditional.ETag([]byte(body))
+
+	for _, c := range []struct {
+		rng, ifRange string
+		code         int
+		contentRange string
+		body         string
+	}{
+		{"", "", 200, "", body},
+		{"bytes=2-4", "", 206, "bytes 2-4/10", "234"},
+		{"bytes=-2", etag, 206, "bytes 8-9/10", "89"},
+		{"bytes=-2", modified.Format(http.TimeFormat), 206, "bytes 8-9/10", "89"},
+		{"bytes=-2", `"stale"`, 200, "", body},
+		{"bytes=20-", "", 416, "bytes */10", ""},
+		{"bytes=0-20,5-", "", 200, "", body},
+		{"lines=1-2", "", 200, "", body},
+		{"bytes=", "", 200, "", body},
+		{"bytes=,", "", 200, "", body},
+		{"bytes=0-1,8-", "", 206, "", ""},
+	} {
+		rw := httptest.NewRecorder()
+		rq := httptest.NewRequest("GET", "http://example.com/", nil)
+		if c.rng != "" {
+			rq.Header.Set("Range", c.rng)
+		}
+		if c.ifRange != "" {
+			rq.Header.Set("If-Range", c.ifRange)
+		}
+		h.ServeHTTP(rw, rq)
+
+		if rw.Code != c.code || rw.Header().Get("Content-Range") != c.contentRange || rw.Header().Get("Accept-Ranges") != "bytes" {
+			t.Errorf("%+v: got %d %v", c, rw.Code, rw.Header())
+		}
+		if c.body != "" && rw.Body.String() != c.body {
+			t.Errorf("%+v: got body %q", c, rw.Body)
+		}
+	}
+}
+
+func TestMultipart(t *testing.T) {
+	rw := httptest.NewRecorder()
+	rq := httptest.NewRequest("GET", "http://example.com/", nil)
+	rq.Header.Set("Range", "bytes=0-1,8-")
+	Serve(rw, rq, strings.NewReader("0123456789"))
+
+	mt, params, err := mime.ParseMediaType(rw.Header().Get("Content-Type"))
+	if rw.Code != 206 || err != nil || mt != "multipart/byteranges" {
+		t.Fatalf("got %d %v", rw.Code, rw.Header())
+	}
+
+	var parts []string
+	mr := multipart.NewReader(rw.Body, params["boundary"])
+	for {
+		p, err := mr.NextPart()
+		if err != nil {
+			break
+		}
+		b, _ := ioutil.ReadAll(p)
+		parts = append(parts, fmt.Sprintf("%s %s %s", p.Header.Get("Content-Type"), p.Header.Get("Content-Range"), b))
+	}
+	expected := "text/plain; charset=utf-8 bytes 0-1/10 01|text/plain; charset=utf-8 bytes 8-9/10 89"
+	if got := strings.Join(parts, "|"); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
@@ -9,21 +9,25 @@ type redirinterface interface {
 	WriteHeader(int)
 }
 
-//implements http.ResponseWriter but really only pretends
+//implements http.ResponseWriter but really only pretends: the short
+//body net/http writes with some redirects is thrown away.
 type redirWrapper struct {
 	redirinterface
 }
 
-const apichange = "The 'net/http'.Redirect functionality appears to have changed, please contact the package owner"
-
-func (redirWrapper) Write([]byte) (int, error) { panic(apichange) }
+func (redirWrapper) Write(p []byte) (int, error) { return len(p), nil }
 
 //Redirect replies to the request with a redirect to url, which may be a path relative
 //to the request path. This function is more generic than the net/http implementation, but
-//is functionally identical.
+//is functionally identical, though if w is not a http.ResponseWriter the body
+//is not written.
 func Redirect(w interface {
 	Header() http.Header
 	WriteHeader(int)
 }, r *http.Request, urlStr string, code int) {
+	if rw, ok := w.(http.ResponseWriter); ok {
+		http.Redirect(rw, r, urlStr, code)
+		return
+	}
 	http.Redirect(redirWrapper{w}, r, urlStr, code)
 }
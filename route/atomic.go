@@ -0,0 +1,126 @@
+package route
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	_ Router        = new(Atomic)
+	_ PathingRouter = new(Atomic)
+	_ DomainRouter  = new(Atomic)
+)
+
+//An Atomic holds a tree of Routers that can be swapped for another while
+//requests are being routed. Each request is routed through the tree held
+//when it reaches the Atomic, so requests in flight finish on the old tree
+//when a new one is stored.
+//
+//An Atomic is transparent, like Named: it descends into its tree as part
+//of any Path or Subdomain trie it is in. The zero value holds nil, which
+//routes nowhere. An Atomic must not be copied once used.
+//
+//The Routers of this package are maps, which must not be changed while
+//they are routing requests. Trees held by an Atomic should instead be
+//replaced: see Update, which edits a copy.
+type Atomic struct {
+	v atomic.Value
+	//serialises Updates
+	mu sync.Mutex
+}
+
+//holder lets an atomic.Value hold Routers of different types.
+type holder struct {
+	Router
+}
+
+//Function NewAtomic returns an Atomic holding r.
+func NewAtomic(r Router) *Atomic {
+	a := new(Atomic)
+	a.Store(r)
+	return a
+}
+
+//Function Load returns the tree held.
+func (a *Atomic) Load() Router {
+	h, _ := a.v.Load().(holder)
+	return h.Router
+}
+
+//Function Store replaces the tree held with r.
+func (a *Atomic) Store(r Router) {
+	a.v.Store(holder{r})
+}
+
+//Function Update replaces the tree held with the tree fn returns, given
+//a copy of the tree held made by Clone. fn may change the copy freely,
+//as no request is routed through it until it is stored. Updates happen
+//one at a time, so none is lost.
+func (a *Atomic) Update(fn func(Router) Router) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.Store(fn(Clone(a.Load())))
+}
+
+func (a *Atomic) RouteHTTP(rq *http.Request) Router {
+	return a.Load()
+}
+
+//Function Child is provided by all types implementing the PathingRouter
+//interface.
+func (a *Atomic) Child(subpath string) (Router, string) {
+	r := a.Load()
+	if p, ok := r.(PathingRouter); ok {
+		return p.Child(subpath)
+	}
+	return r, subpath
+}
+
+//Function Subdomain is provided by all types implementing the DomainRouter
+//interface.
+func (a *Atomic) Subdomain(subpath string) (Router, string) {
+	r := a.Load()
+	if d, ok := r.(DomainRouter); ok {
+		return d.Subdomain(subpath)
+	}
+	return r, subpath
+}
+
+//Function Clone returns a deep copy of the tree r, which can be changed
+//without changing r. The maps of the Routers of this package are copied;
+//Handlers and other Routers are shared, since they cannot be changed
+//in place.
+func Clone(r Router) Router {
+	return rewriter{
+		seen: make(map[mapKey]interface{}),
+	}.rewrite(r)
+}
+
+//Function With returns a copy of p, with the name name routing to r.
+//p itself is not changed, so an edit deep in a tree can be made by copying
+//only the Paths on the way to it:
+//
+//	root = root.With("api", root["api"].(Path).With("v2", v2))
+func (p Path) With(name string, r Router) Path {
+	return Path(with(p, name, r))
+}
+
+//Function With is Path.With for Subdomains.
+func (s Subdomain) With(name string, r Router) Subdomain {
+	return Subdomain(with(s, name, r))
+}
+
+//Function With is Path.With for Verbs.
+func (v Verb) With(method string, r Router) Verb {
+	return Verb(with(v, method, r))
+}
+
+func with(m map[string]Router, key string, r Router) map[string]Router {
+	c := make(map[string]Router, len(m)+1)
+	for k, v := range m {
+		c[k] = v
+	}
+	c[key] = r
+	return c
+}
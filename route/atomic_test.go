@@ -0,0 +1,84 @@
+package route
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func text(s string) Handler {
+	return HandleFunc(func(rw http.ResponseWriter, rq *http.Request) {
+		fmt.Fprint(rw, s)
+	})
+}
+
+func TestAtomic(t *testing.T) {
+	tenants := NewAtomic(Path{
+		"a": text("a"),
+	})
+	h := RouteHandler{
+		Router: Path{
+			"tenants": tenants,
+		},
+		NotFound: NotFound,
+	}
+
+	if got := serve(h, "GET", "http://example.com/tenants/a").Body.String(); got != "a" {
+		t.Fatalf("expected %q, got %q", "a", got)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			tenants.Update(func(r Router) Router {
+				p := r.(Path)
+				p[fmt.Sprint(i)] = text(fmt.Sprint(i))
+				return p
+			})
+		}(i)
+		go func() {
+			defer wg.Done()
+			serve(h, "GET", "http://example.com/tenants/a")
+		}()
+	}
+	wg.Wait()
+
+	for i := 0; i < 10; i++ {
+		url := fmt.Sprintf("http://example.com/tenants/%d", i)
+		if got := serve(h, "GET", url).Body.String(); got != fmt.Sprint(i) {
+			t.Errorf("%s: expected %q, got %q", url, fmt.Sprint(i), got)
+		}
+	}
+
+	old := tenants.Load().(Path)
+	tenants.Store(old.With("b", text("b")))
+	if _, ok := old["b"]; ok {
+		t.Error("With changed the Path it was called on")
+	}
+	if got := serve(h, "GET", "http://example.com/tenants/b").Body.String(); got != "b" {
+		t.Errorf("expected %q, got %q", "b", got)
+	}
+}
+
+func TestClone(t *testing.T) {
+	users := Path{"&id": text("user")}
+	tree := Subdomain{
+		"example.com": Path{
+			"users": users,
+			"again": users,
+		},
+	}
+	c := Clone(tree).(Subdomain)
+	cp := c["example.com"].(Path)
+	cp["users"].(Path)["new"] = text("new")
+
+	if _, ok := users["new"]; ok {
+		t.Error("changing the clone changed the original")
+	}
+	if _, ok := cp["again"].(Path)["new"]; !ok {
+		t.Error("a Path shared in the original is not shared in the clone")
+	}
+}
@@ -0,0 +1,91 @@
+package route
+
+import (
+	"github.com/TShadwell/fweight"
+	"net/http"
+	"net/url"
+	Pathp "path"
+	"strings"
+)
+
+//A PathPolicy is what a RouteHandler does with a request whose URL path
+//is not canonical. Paths routes /a, /a/ and //a/./ alike, but only one of
+//them is the canonical spelling: that cleaned by path.Clean, with a trailing
+//slash if and only if the RouteHandler's TrailingSlash is set. The root
+//is always "/". The policy also applies to the spelling of the names
+//matched by FoldPaths.
+//
+//The path is checked once the route is found. The part of it a Mount hands
+//on to its Handler keeps its trailing slash, or lack of one, since the
+//Handler decides that: a http.FileServer redirects directories to the path
+//with a slash, and files to that without.
+type PathPolicy uint8
+
+const (
+	//PathLenient routes every spelling of a path as the canonical one.
+	PathLenient PathPolicy = iota
+	//PathStrict routes only the canonical spelling. Any other is
+	//not found.
+	PathStrict
+	//PathRedirect redirects to the canonical spelling, keeping the
+	//query. GET and HEAD requests are redirected with
+	//301 Moved Permanently, others with 308 Permanent Redirect so
+	//that the method and body are kept.
+	PathRedirect
+)
+
+//Function CanonicalPath returns the canonical spelling of the URL path
+//urlPath, which may be escaped. See PathPolicy.
+func CanonicalPath(urlPath string, trailingSlash bool) string {
+	c := Pathp.Clean("/" + urlPath)
+	if trailingSlash && c != "/" {
+		c += "/"
+	}
+	return c
+}
+
+//Function checkPath returns the Router to route rq to in place of the
+//Handler found for it if its URL path is not canonical, and whether it
+//should be.
+func (s *state) checkPath(rq *http.Request) (Router, bool) {
+	if s.paths == PathLenient {
+		return nil, false
+	}
+
+	path := rq.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	trailingSlash := s.trailingSlash
+	if s.handedOn {
+		trailingSlash = strings.HasSuffix(path, "/")
+	}
+	canonical := CanonicalPath(path, trailingSlash)
+	if s.respelled {
+		canonical = CanonicalPath((&url.URL{Path: s.canonical}).EscapedPath(), trailingSlash)
+	}
+	if path == canonical {
+		return nil, false
+	}
+
+	if s.paths == PathStrict {
+		return nil, true
+	}
+
+	return redirectTo(rq, canonical), true
+}
+
+//Function redirectTo returns a Handler redirecting rq to the same URL with
+//the escaped path path, as PathRedirect does.
+func redirectTo(rq *http.Request, path string) Router {
+	u := *rq.URL
+	u.RawPath = path
+	u.Path, _ = url.PathUnescape(path)
+	return HandleFunc(func(rw http.ResponseWriter, rq *http.Request) {
+		code := fweight.StatusPermanentRedirect
+		if rq.Method == "GET" || rq.Method == "HEAD" {
+			code = fweight.StatusMovedPermanently
+		}
+		fweight.Redirect(rw, rq, u.RequestURI(), int(code))
+	})
+}
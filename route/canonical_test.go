@@ -0,0 +1,65 @@
+package route
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPathPolicy(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "d"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "f.txt"), []byte("f"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tree := Path{
+		"": paramsHandler(),
+		"a": Path{
+			"b": paramsHandler(),
+		},
+		"s": Mount{http.FileServer(http.Dir(dir))},
+	}
+
+	for _, c := range []struct {
+		policy        PathPolicy
+		trailingSlash bool
+		method, url   string
+		code          int
+		location      string
+	}{
+		{PathLenient, false, "GET", "http://example.com//a/./b/", 200, ""},
+		{PathStrict, false, "GET", "http://example.com/a/b", 200, ""},
+		{PathStrict, false, "GET", "http://example.com/a/b/", 404, ""},
+		{PathStrict, false, "GET", "http://example.com/", 200, ""},
+		{PathStrict, true, "GET", "http://example.com/a/b", 404, ""},
+		{PathStrict, true, "GET", "http://example.com/a/b/", 200, ""},
+		{PathRedirect, false, "GET", "http://example.com/a/b", 200, ""},
+		{PathRedirect, false, "GET", "http://example.com/a//b/?q=1", 301, "/a/b?q=1"},
+		{PathRedirect, false, "POST", "http://example.com/a/b/", 308, "/a/b"},
+		{PathRedirect, true, "HEAD", "http://example.com/a/../a/b", 301, "/a/b/"},
+		{PathRedirect, true, "GET", "http://example.com", 200, ""},
+		{PathRedirect, false, "GET", "http://example.com//a%2Fb/", 301, "/a%2Fb"},
+		//the Handler of a Mount decides whether its part ends in a slash
+		{PathRedirect, false, "GET", "http://example.com/s/d/", 200, ""},
+		{PathRedirect, false, "GET", "http://example.com/s/d", 301, "d/"},
+		{PathRedirect, true, "GET", "http://example.com/s/f.txt", 200, ""},
+		{PathRedirect, false, "GET", "http://example.com//s/./d/", 301, "/s/d/"},
+		{PathRedirect, true, "GET", "http://example.com/s", 301, "/s/"},
+		{PathStrict, false, "GET", "http://example.com/s/d/", 200, ""},
+	} {
+		h := RouteHandler{
+			Router:        tree,
+			NotFound:      NotFound,
+			Paths:         c.policy,
+			TrailingSlash: c.trailingSlash,
+		}
+		rw := serve(h, c.method, c.url)
+		if rw.Code != c.code || rw.Header().Get("Location") != c.location {
+			t.Errorf("%+v: got %d %q", c, rw.Code, rw.Header().Get("Location"))
+		}
+	}
+}
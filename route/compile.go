@@ -0,0 +1,288 @@
+package route
+
+import (
+	"net/http"
+	Pathp "path"
+	"reflect"
+	"strings"
+)
+
+var (
+	_ PathingRouter = new(Compiled)
+	_ Router        = new(Compiled)
+)
+
+//A Compiled is an immutable radix tree compiled from a trie of Paths and
+//NoExtPaths by Compile. It routes exactly as the trie did when it was
+//compiled, but looks each path up without allocating, unless a segment
+//is captured.
+//
+//Chains of Paths with only one name are merged into a single edge of the
+//tree, so /a/b/c through Paths holding only "b" and "c" is one step.
+//PathingRouters other than Path and NoExtPath are not compiled: the route
+//continues through them as it would in PathRouteHTTP.
+type Compiled struct {
+	root *node
+	//the Router compiled, which routes in place of root when it is
+	//not a Path or NoExtPath, and is walked by Routes and URL
+	router Router
+}
+
+//A node is a compiled Path.
+type node struct {
+	noExt bool
+	//the empty name
+	here *target
+	//keyed by the first name of each edge
+	edges map[string]radixEdge
+	amp   *target
+	wild  *target
+}
+
+//A target is a Router a node leads to, compiled.
+type target struct {
+	Router
+	//set if Router is a compiled Path
+	node *node
+	//the name to capture under, if any
+	capture string
+	rest    bool
+}
+
+//An edge of the radix tree. The names in rest must follow the first
+//for the path to reach target.
+type radixEdge struct {
+	rest   []string
+	target *target
+}
+
+//Function Compile compiles the trie of Paths r. Later changes to the
+//Paths in r are not seen by the Compiled. If r is not a Path or NoExtPath,
+//the Compiled routes as r does. Compile(nil) routes nothing, as an empty
+//Path.
+func Compile(r Router) *Compiled {
+	if r == nil {
+		r = Path{}
+	}
+	c := compiler{
+		nodes: make(map[mapKey]*node),
+	}
+	if n := c.node(r); n != nil {
+		return &Compiled{root: n, router: r}
+	}
+	return &Compiled{router: r}
+}
+
+type compiler struct {
+	//compiled nodes by the type and address of their Path, so that
+	//shared and recursive tries compile to shared and recursive trees,
+	//and a map used as both a Path and a NoExtPath compiles to both.
+	nodes map[mapKey]*node
+}
+
+func asPath(r Router) (p Path, noExt bool, ok bool) {
+	switch t := r.(type) {
+	case Path:
+		return t, false, true
+	case NoExtPath:
+		return Path(t), true, true
+	}
+	return
+}
+
+func (c compiler) node(r Router) *node {
+	p, noExt, ok := asPath(r)
+	if !ok {
+		return nil
+	}
+	key := mapKey{reflect.TypeOf(r), reflect.ValueOf(p).Pointer()}
+	if n, ok := c.nodes[key]; ok {
+		return n
+	}
+
+	n := &node{
+		noExt: noExt,
+		edges: make(map[string]radixEdge, len(p)),
+	}
+	c.nodes[key] = n
+
+	for k, v := range p {
+		switch {
+		case k == "":
+			n.here = c.target(v, "", false)
+			continue
+		case v == nil:
+		case isWildcard(k) && p["*"] == nil || k == "*":
+			n.wild = c.target(v, wildcardName(k), true)
+		case isAmpersand(k) && p["&"] == nil || k == "&":
+			n.amp = c.target(v, k[1:], false)
+		}
+
+		//special keys are not names, as in Path.ChildProcess
+		if !isSpecial(k) {
+			n.edges[k] = c.edge(v)
+		}
+	}
+
+	if n.here == nil {
+		n.here = n.wild
+	}
+	return n
+}
+
+func wildcardName(k string) string {
+	if k == "*" {
+		return k
+	}
+	return k[1:]
+}
+
+func (c compiler) target(r Router, capture string, rest bool) *target {
+	if r == nil {
+		return nil
+	}
+	t := &target{
+		Router:  r,
+		capture: capture,
+		rest:    rest,
+	}
+	if !rest {
+		t.node = c.node(r)
+	}
+	return t
+}
+
+//Function edge compiles the edge to r, merging the names of any chain of
+//Paths with only one name into it.
+func (c compiler) edge(r Router) (e radixEdge) {
+	seen := make(map[uintptr]bool)
+	for {
+		p, ok := r.(Path)
+		if !ok || len(p) != 1 {
+			break
+		}
+		addr := reflect.ValueOf(p).Pointer()
+		if seen[addr] {
+			break
+		}
+		seen[addr] = true
+
+		var k string
+		for k, r = range p {
+		}
+		if isSpecial(k) {
+			r = p
+			break
+		}
+		e.rest = append(e.rest, k)
+	}
+	if r != nil {
+		e.target = c.target(r, "", false)
+	}
+	return
+}
+
+//Function child is Path.ChildProcess for nodes.
+func (n *node) child(subpath string) (t *target, value, remaining string) {
+	subpath = strings.TrimLeft(subpath, "/")
+	if subpath == "" {
+		return n.here, "", ""
+	}
+
+	remaining, popped := swallowOne(subpath)
+	key := popped
+	if n.noExt {
+		key = strings.TrimSuffix(popped, Pathp.Ext(popped))
+	}
+
+	if e, ok := n.edges[key]; ok {
+		for _, name := range e.rest {
+			var next string
+			remaining, next = swallowOne(strings.TrimLeft(remaining, "/"))
+			if next != name {
+				return nil, "", ""
+			}
+		}
+		return e.target, "", remaining
+	}
+
+	if n.amp != nil {
+		return n.amp, popped, remaining
+	}
+
+	if n.wild != nil {
+		return n.wild, subpath, ""
+	}
+
+	return nil, "", subpath
+}
+
+//RouteHTTP routes the request through the tree, as PathRouteHTTP would
+//through the trie it was compiled from.
+func (c *Compiled) RouteHTTP(rq *http.Request) Router {
+	if c.root == nil {
+		return c.router.RouteHTTP(rq)
+	}
+
+	n, path := c.root, cleanPath(rq.URL.Path)
+	s := peekState(rq)
+	for {
+		t, value, remaining := n.child(path)
+		if s != nil && s.tracer != nil {
+			var r Router
+			if t != nil {
+				r = t.Router
+			}
+			s.step(c, segment(path), remaining, r)
+		}
+		if t == nil {
+			return nil
+		}
+		if t.capture != "" {
+			stateOf(rq).capture(Capture{
+				Name:  t.capture,
+				Value: value,
+			})
+		}
+
+		switch {
+		case t.rest:
+			return t.Router
+		case t.node != nil:
+			n, path = t.node, remaining
+			continue
+		}
+
+		if p, ok := t.Router.(PathingRouter); ok {
+			return pathDescend(p, rq, remaining)
+		}
+		return t.Router
+	}
+}
+
+//Function Child is provided by all types implementing the PathingRouter
+//interface.
+func (c *Compiled) Child(subpath string) (Router, string) {
+	if c.root == nil {
+		if p, ok := c.router.(PathingRouter); ok {
+			return p.Child(subpath)
+		}
+		return c.router, subpath
+	}
+
+	t, value, remaining := c.root.child(subpath)
+	switch {
+	case t == nil:
+		return nil, remaining
+	case t.capture != "":
+		return captured{
+			Capture: Capture{
+				Name:  t.capture,
+				Value: value,
+			},
+			Router: t.Router,
+			rest:   t.rest,
+		}, remaining
+	}
+	return t.Router, remaining
+}
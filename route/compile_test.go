@@ -0,0 +1,156 @@
+package route
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// a comparable http.Handler
+type named string
+
+func (n named) ServeHTTP(rw http.ResponseWriter, rq *http.Request) {
+	rw.Write([]byte(n))
+}
+
+var compileTree = func() Path {
+	files := Path{
+		"&file": Handle(named("file")),
+	}
+	//used both as a Path and a NoExtPath
+	both := Path{
+		"a": Handle(named("a")),
+		"b": Handle(named("b")),
+	}
+	return Path{
+		"":      Handle(named("index")),
+		"about": Handle(named("about")),
+		"a": Path{
+			"b": Path{
+				"c": Path{
+					"":  Handle(named("abc")),
+					"d": Handle(named("abcd")),
+				},
+			},
+		},
+		"users": Path{
+			"":   Handle(named("users")),
+			"me": Handle(named("me")),
+			"&id": Path{
+				"":      Handle(named("user")),
+				"files": files,
+			},
+		},
+		"docs":   NoExtPath{"intro": Handle(named("intro"))},
+		"static": Path{"*file": Handle(named("static"))},
+		"typed":  TypedPath{}.Match("n", Int, Handle(named("typed"))),
+		"gone":   nil,
+		"shared": files,
+		"x":      both,
+		"y":      NoExtPath(both),
+	}
+}()
+
+var compileURLs = []string{
+	"/", "", "//", "/about", "/about/", "/about/more", "/nothing",
+	"/a", "/a/b", "/a/b/c", "/a/b/c/", "/a/b/c/d", "/a/b/x", "/a/x/c",
+	"/users", "/users/me", "/users/bob", "/users/bob/files", "/users/bob/files/cat.png",
+	"/docs/intro.html", "/docs/intro", "/docs/other.html",
+	"/static", "/static/", "/static/css/site.css",
+	"/typed/12", "/typed/x", "/gone", "/shared/x", "/./a/../about",
+	"/users/&id", "/users/&id/files/&file", "/static/*file",
+	"/x/a", "/x/a.html", "/y/a", "/y/a.html", "/y/b.txt",
+}
+
+func routeString(r Router, url string) string {
+	rq, err := http.NewRequest("GET", "http://example.com"+url, nil)
+	if err != nil {
+		panic(err)
+	}
+	rq = withState(rq)
+	if r = r.RouteHTTP(rq); r == nil {
+		return "<nil>"
+	}
+	h, ok := r.(Handler)
+	if !ok {
+		return "<not a handler>"
+	}
+	var params string
+	for _, c := range Params(rq) {
+		params += ";" + c.Name + "=" + c.Value
+	}
+	return string(h.Handler.(named)) + params
+}
+
+func TestCompile(t *testing.T) {
+	//maps are compiled in random order, which must not matter
+	for i := 0; i < 20; i++ {
+		c := Compile(compileTree)
+		for _, u := range compileURLs {
+			expected := routeString(compileTree, u)
+			got := routeString(c, u)
+			if expected != got {
+				t.Errorf("%+q: Path routed to %q, Compiled to %q", u, expected, got)
+			}
+		}
+	}
+}
+
+func TestCompileRoutes(t *testing.T) {
+	describe := func(routes []Route) (s []string) {
+		for _, r := range routes {
+			s = append(s, fmt.Sprintf("%s %s %s %v", r.Path, r.Name, r.Handler, r.Opaque))
+		}
+		return
+	}
+	expected, got := describe(Routes(compileTree)), describe(Routes(Compile(compileTree)))
+	if strings.Join(expected, "\n") != strings.Join(got, "\n") {
+		t.Errorf("expected the routes\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestCompileNil(t *testing.T) {
+	h := RouteHandler{Router: Compile(nil), NotFound: NotFound}
+	for _, u := range []string{"http://example.com/", "http://example.com/a/b"} {
+		if rw := serve(h, "GET", u); rw.Code != http.StatusNotFound {
+			t.Errorf("%s: expected 404, got %d", u, rw.Code)
+		}
+	}
+}
+
+func TestCompileAllocs(t *testing.T) {
+	c := Compile(compileTree)
+	rq, _ := http.NewRequest("GET", "http://example.com/a/b/c/d", nil)
+	if n := testing.AllocsPerRun(100, func() { c.RouteHTTP(rq) }); n != 0 {
+		t.Errorf("expected no allocations, got %v", n)
+	}
+}
+
+func benchmarkRoute(b *testing.B, r Router, url string) {
+	rq, _ := http.NewRequest("GET", "http://example.com"+url, nil)
+	rq = withState(rq)
+	s := stateOf(rq)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.captures = s.captures[:0]
+		r.RouteHTTP(rq)
+	}
+}
+
+func BenchmarkPathStatic(b *testing.B) {
+	benchmarkRoute(b, compileTree, "/a/b/c/d")
+}
+
+func BenchmarkCompiledStatic(b *testing.B) {
+	benchmarkRoute(b, Compile(compileTree), "/a/b/c/d")
+}
+
+func BenchmarkPathCapture(b *testing.B) {
+	benchmarkRoute(b, compileTree, "/users/bob/files/cat.png")
+}
+
+func BenchmarkCompiledCapture(b *testing.B) {
+	benchmarkRoute(b, Compile(compileTree), "/users/bob/files/cat.png")
+}
@@ -0,0 +1,398 @@
+//Package config builds trees of Routers from JSON documents, so that
+//routes can be changed without a change to the code.
+//
+//A document is a route. Each route is an object with exactly one of:
+//
+//	"handler":  the name of a http.Handler in the Handlers given
+//	"mount":    the same, mounted with route.Mount
+//	"static":   a directory, served by a http.FileServer with route.Mount
+//	"redirect": a URL to redirect to, with "status" (default 301)
+//	"paths":    routes by path name, as a route.Path
+//	"methods":  routes by method, as a route.Verb
+//	"hosts":    routes by domain, as a route.Subdomain
+//
+//and optionally "name", naming it as route.Name does:
+//
+//	{"hosts": {
+//		"example.com": {"paths": {
+//			"":       {"handler": "home"},
+//			"static": {"static": "/var/www/static"},
+//			"old":    {"redirect": "/new"},
+//			"users":  {"paths": {
+//				"&id": {"name": "user", "methods": {
+//					"GET":  {"handler": "user"},
+//					"POST": {"handler": "updateUser"}
+//				}}
+//			}}
+//		}}
+//	}}
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/TShadwell/fweight"
+	"github.com/TShadwell/fweight/route"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+//Handlers are the http.Handlers a document can refer to, by name.
+type Handlers map[string]http.Handler
+
+//An Error is a problem with a document. At is where in the document the
+//problem is, like `hosts["example.com"].paths["users"]`, or empty if it is
+//with the document as a whole.
+type Error struct {
+	At  string
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.At == "" {
+		return "config: " + e.Err.Error()
+	}
+	return fmt.Sprintf("config: %s: %v", e.At, e.Err)
+}
+
+//An UnknownHandler is the name of a handler that is not in the Handlers.
+type UnknownHandler string
+
+func (u UnknownHandler) Error() string {
+	return fmt.Sprintf("unknown handler %+q", string(u))
+}
+
+//A DuplicateKey is a key given more than once in the same object.
+type DuplicateKey string
+
+func (d DuplicateKey) Error() string {
+	return fmt.Sprintf("duplicate key %+q", string(d))
+}
+
+//A route of a document.
+type node struct {
+	Name     string           `json:"name"`
+	Handler  string           `json:"handler"`
+	Mount    string           `json:"mount"`
+	Static   string           `json:"static"`
+	Redirect string           `json:"redirect"`
+	Status   int              `json:"status"`
+	Paths    map[string]*node `json:"paths"`
+	Methods  map[string]*node `json:"methods"`
+	Hosts    map[string]*node `json:"hosts"`
+}
+
+//Function Parse builds the tree of Routers described by the document data,
+//with the handlers h. Any problem is returned as an *Error.
+func Parse(data []byte, h Handlers) (route.Router, error) {
+	if err := checkDuplicates(data); err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var n node
+	if err := dec.Decode(&n); err != nil {
+		return nil, decodeError(data, err)
+	}
+
+	r, err := builder{h}.build(&n, "")
+	if err != nil {
+		return nil, err
+	}
+
+	//hosts are checked as a whole, since Subdomains can shadow each other
+	if err := route.CheckDomains(r); err != nil {
+		return nil, &Error{Err: err}
+	}
+	return r, nil
+}
+
+//Function Load is Parse for the file named filename.
+func Load(filename string, h Handlers) (route.Router, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data, h)
+}
+
+//Function Reload loads the file named filename into a, for example when
+//a running server is sent SIGHUP:
+//
+//	tree := route.NewAtomic(nil)
+//	if err := config.Reload(tree, "routes.json", handlers); err != nil {
+//		log.Fatal(err)
+//	}
+//	go func() {
+//		for range hup {
+//			if err := config.Reload(tree, "routes.json", handlers); err != nil {
+//				log.Println(err)
+//			}
+//		}
+//	}()
+//	http.ListenAndServe(":80", route.RouteHandler{Router: tree})
+//
+//If the file cannot be loaded, a keeps the tree it held, and the error is
+//returned. Requests being routed when the tree is replaced finish on the
+//old tree.
+func Reload(a *route.Atomic, filename string, h Handlers) error {
+	r, err := Load(filename, h)
+	if err != nil {
+		return err
+	}
+	a.Store(r)
+	return nil
+}
+
+//Function decodeError returns an *Error for the error err from decoding data.
+func decodeError(data []byte, err error) error {
+	switch e := err.(type) {
+	case *json.SyntaxError:
+		return &Error{At: position(data, e.Offset), Err: err}
+	case *json.UnmarshalTypeError:
+		return &Error{At: position(data, e.Offset), Err: fmt.Errorf("%s must be %s, not %s", e.Field, e.Type, e.Value)}
+	}
+	return &Error{Err: err}
+}
+
+//Function position returns the line and column of offset in data.
+func position(data []byte, offset int64) string {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	col := len(before) - bytes.LastIndexByte(before, '\n')
+	return fmt.Sprintf("line %d, column %d", line, col)
+}
+
+//Function checkDuplicates returns an *Error for the first key given twice
+//in the same object of the JSON document data, which encoding/json would
+//otherwise let the last win.
+func checkDuplicates(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var walk func(at string) error
+	walk = func(at string) error {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch t {
+		case json.Delim('{'):
+			seen := make(map[string]bool)
+			for dec.More() {
+				t, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				key := t.(string)
+				if seen[key] {
+					return &Error{At: at, Err: DuplicateKey(key)}
+				}
+				seen[key] = true
+				if err := walk(child(at, key)); err != nil {
+					return err
+				}
+			}
+		case json.Delim('['):
+			for i := 0; dec.More(); i++ {
+				if err := walk(fmt.Sprintf("%s[%d]", at, i)); err != nil {
+					return err
+				}
+			}
+		default:
+			return nil
+		}
+		//the closing delimiter
+		_, err = dec.Token()
+		return err
+	}
+
+	//syntax errors are left to be reported by Parse
+	if err, ok := walk("").(*Error); ok {
+		return err
+	}
+	return nil
+}
+
+//Function child returns the place of key within the object at at, keys
+//of routes being written as in Go (`paths["users"]`).
+func child(at, key string) string {
+	switch {
+	case strings.HasSuffix(at, "paths"), strings.HasSuffix(at, "methods"), strings.HasSuffix(at, "hosts"):
+		return fmt.Sprintf("%s[%+q]", at, key)
+	case at == "":
+		return key
+	}
+	return at + "." + key
+}
+
+type builder struct {
+	handlers Handlers
+}
+
+func (b builder) handler(name string) (http.Handler, error) {
+	h, ok := b.handlers[name]
+	if !ok || h == nil {
+		return nil, UnknownHandler(name)
+	}
+	return h, nil
+}
+
+//Function build builds the route n, found at at.
+func (b builder) build(n *node, at string) (r route.Router, err error) {
+	if n == nil {
+		return nil, &Error{At: at, Err: fmt.Errorf("route is null")}
+	}
+
+	var kinds []string
+	for kind, set := range map[string]bool{
+		"handler":  n.Handler != "",
+		"mount":    n.Mount != "",
+		"static":   n.Static != "",
+		"redirect": n.Redirect != "",
+		"paths":    n.Paths != nil,
+		"methods":  n.Methods != nil,
+		"hosts":    n.Hosts != nil,
+	} {
+		if set {
+			kinds = append(kinds, kind)
+		}
+	}
+	sort.Strings(kinds)
+	switch len(kinds) {
+	case 0:
+		return nil, &Error{At: at, Err: fmt.Errorf("route has none of handler, mount, static, redirect, paths, methods or hosts")}
+	case 1:
+	default:
+		return nil, &Error{At: at, Err: fmt.Errorf("route has conflicting keys %s", strings.Join(kinds, ", "))}
+	}
+	if n.Status != 0 && n.Redirect == "" {
+		return nil, &Error{At: at, Err: fmt.Errorf("status is only used with redirect")}
+	}
+
+	switch kinds[0] {
+	case "handler":
+		var h http.Handler
+		if h, err = b.handler(n.Handler); err != nil {
+			return nil, &Error{At: child(at, "handler"), Err: err}
+		}
+		r = route.Handle(h)
+	case "mount":
+		var h http.Handler
+		if h, err = b.handler(n.Mount); err != nil {
+			return nil, &Error{At: child(at, "mount"), Err: err}
+		}
+		r = route.Mount{Handler: h}
+	case "static":
+		r = route.Mount{Handler: http.FileServer(http.Dir(n.Static))}
+	case "redirect":
+		if r, err = redirect(n.Redirect, n.Status); err != nil {
+			return nil, &Error{At: child(at, "status"), Err: err}
+		}
+	case "paths":
+		r, err = b.paths(n.Paths, child(at, "paths"))
+	case "methods":
+		r, err = b.methods(n.Methods, child(at, "methods"))
+	case "hosts":
+		r, err = b.hosts(n.Hosts, child(at, "hosts"))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if n.Name != "" {
+		r = route.Name(n.Name, r)
+	}
+	return r, nil
+}
+
+func redirect(url string, status int) (route.Router, error) {
+	if status == 0 {
+		status = int(fweight.StatusMovedPermanently)
+	}
+	if status < 300 || status > 399 {
+		return nil, fmt.Errorf("%d is not a redirect status", status)
+	}
+	return route.HandleFunc(func(rw http.ResponseWriter, rq *http.Request) {
+		fweight.Redirect(rw, rq, url, status)
+	}), nil
+}
+
+//Function sorted returns the keys of m, sorted so that errors are reported
+//in a stable order.
+func sorted(m map[string]*node) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+//Function children builds the routes of m, found at at, calling check on
+//each key first.
+func (b builder) children(m map[string]*node, at string, check func(key string) error) (map[string]route.Router, error) {
+	c := make(map[string]route.Router, len(m))
+	for _, k := range sorted(m) {
+		if err := check(k); err != nil {
+			return nil, &Error{At: at, Err: err}
+		}
+		r, err := b.build(m[k], child(at, k))
+		if err != nil {
+			return nil, err
+		}
+		c[k] = r
+	}
+	return c, nil
+}
+
+func (b builder) paths(m map[string]*node, at string) (route.Router, error) {
+	var ampersand, wildcard string
+	c, err := b.children(m, at, func(k string) error {
+		switch {
+		case strings.Contains(k, "/"):
+			return fmt.Errorf("path name %+q contains a slash; use nested paths", k)
+		case strings.HasPrefix(k, "&"):
+			if ampersand != "" {
+				return fmt.Errorf("conflicting keys %+q and %+q: a path can have only one ampersand", ampersand, k)
+			}
+			ampersand = k
+		case strings.HasPrefix(k, "*"):
+			if wildcard != "" {
+				return fmt.Errorf("conflicting keys %+q and %+q: a path can have only one wildcard", wildcard, k)
+			}
+			wildcard = k
+		}
+		return nil
+	})
+	return route.Path(c), err
+}
+
+func (b builder) methods(m map[string]*node, at string) (route.Router, error) {
+	c, err := b.children(m, at, func(k string) error {
+		if k == "" || k != strings.ToUpper(k) || strings.ContainsAny(k, " \t/") {
+			return fmt.Errorf("%+q is not a method; methods are upper case", k)
+		}
+		return nil
+	})
+	return route.Verb(c), err
+}
+
+func (b builder) hosts(m map[string]*node, at string) (route.Router, error) {
+	var wildcard string
+	c, err := b.children(m, at, func(k string) error {
+		if strings.HasPrefix(k, "&") {
+			if wildcard != "" {
+				return fmt.Errorf("conflicting keys %+q and %+q: hosts can have only one wildcard", wildcard, k)
+			}
+			wildcard = k
+		}
+		return nil
+	})
+	return route.Subdomain(c), err
+}
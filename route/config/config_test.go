@@ -0,0 +1,135 @@
+package config
+
+import (
+	"fmt"
+	"github.com/TShadwell/fweight/route"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func text(s string) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, rq *http.Request) {
+		fmt.Fprint(rw, s, route.Param(rq, "id"))
+	})
+}
+
+var handlers = Handlers{
+	"home":   text("home"),
+	"user":   text("user "),
+	"update": text("update "),
+}
+
+func serve(h http.Handler, method, url string) *httptest.ResponseRecorder {
+	rw := httptest.NewRecorder()
+	rq := httptest.NewRequest(method, url, nil)
+	h.ServeHTTP(rw, rq)
+	return rw
+}
+
+func TestParse(t *testing.T) {
+	r, err := Parse([]byte(`{"hosts": {
+		"example.com": {"paths": {
+			"":      {"handler": "home"},
+			"old":   {"redirect": "/new", "status": 302},
+			"users": {"paths": {
+				"&id": {"name": "user", "methods": {
+					"GET":  {"handler": "user"},
+					"POST": {"handler": "update"}
+				}}
+			}}
+		}}
+	}}`), handlers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := route.RouteHandler{Router: r, NotFound: route.NotFound}
+
+	for _, c := range []struct {
+		method, url string
+		code        int
+		body        string
+	}{
+		{"GET", "http://example.com/", 200, "home"},
+		{"GET", "http://example.com/users/bob", 200, "user bob"},
+		{"POST", "http://example.com/users/bob", 200, "update bob"},
+		{"GET", "http://example.com/old", 302, ""},
+		{"GET", "http://example.org/", 404, ""},
+	} {
+		rw := serve(h, c.method, c.url)
+		if rw.Code != c.code || c.body != "" && rw.Body.String() != c.body {
+			t.Errorf("%s %s: expected %d %q, got %d %q", c.method, c.url, c.code, c.body, rw.Code, rw.Body)
+		}
+	}
+
+	if u, err := route.URL(r, "user", "id", "anne"); err != nil || u.String() != "//example.com/users/anne" {
+		t.Errorf("expected the named route, got %v %v", u, err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for doc, expected := range map[string]string{
+		`{"paths": {"a": {"handler": "nope"}}}`:                             `config: paths["a"].handler: unknown handler "nope"`,
+		`{"paths": {"a": {"handler": "home"}, "a": {"static": "."}}}`:       `config: paths: duplicate key "a"`,
+		`{"paths": {"a": {"handler": "home", "static": "."}}}`:              `config: paths["a"]: route has conflicting keys handler, static`,
+		`{"paths": {"&a": {"handler": "home"}, "&b": {"handler": "home"}}}`: `config: paths: conflicting keys "&a" and "&b": a path can have only one ampersand`,
+		`{"paths": {"a/b": {"handler": "home"}}}`:                           `config: paths: path name "a/b" contains a slash; use nested paths`,
+		`{"methods": {"get": {"handler": "home"}}}`:                         `config: methods: "get" is not a method; methods are upper case`,
+		`{"redirect": "/", "status": 200}`:                                  `config: status: 200 is not a redirect status`,
+		`{"paths": {"a": {}}}`:                                              `config: paths["a"]: route has none of handler, mount, static, redirect, paths, methods or hosts`,
+		`{"handlr": "home"}`:                                                `config: json: unknown field "handlr"`,
+		"{\"paths\": {\n\"a\": {\"handler\": 1}}}":                          `config: line 2, column 19: paths.a.handler must be string, not number`,
+		"{\"paths\": {\n\"a\": }}":                                          `config: line 2, column 7: invalid character '}' looking for beginning of value`,
+	} {
+		_, err := Parse([]byte(doc), handlers)
+		if err == nil || err.Error() != expected {
+			t.Errorf("%s:\nexpected %s\ngot      %v", doc, expected, err)
+		}
+	}
+}
+
+func TestReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "routes.json")
+
+	write := func(doc string) {
+		if err := ioutil.WriteFile(file, []byte(doc), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tree := route.NewAtomic(nil)
+	h := route.RouteHandler{Router: tree, NotFound: route.NotFound}
+
+	write(`{"paths": {"a": {"handler": "home"}}}`)
+	if err := Reload(tree, file, handlers); err != nil {
+		t.Fatal(err)
+	}
+	if rw := serve(h, "GET", "http://example.com/a"); rw.Code != 200 {
+		t.Errorf("expected /a to be routed, got %d", rw.Code)
+	}
+
+	write(`{"paths": {"b": {"handler": "home"}}}`)
+	if err := Reload(tree, file, handlers); err != nil {
+		t.Fatal(err)
+	}
+	if rw := serve(h, "GET", "http://example.com/a"); rw.Code != 404 {
+		t.Errorf("expected /a to be gone, got %d", rw.Code)
+	}
+
+	write(`{"paths": {"c": {"handler": "missing"}}}`)
+	if err := Reload(tree, file, handlers); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("expected an unknown handler error, got %v", err)
+	}
+	if rw := serve(h, "GET", "http://example.com/b"); rw.Code != 200 {
+		t.Errorf("expected the last good tree to be kept, got %d", rw.Code)
+	}
+}
@@ -0,0 +1,123 @@
+package route
+
+import (
+	"net/http"
+	Pathp "path"
+	"strings"
+	"unicode"
+)
+
+var (
+	_ PathingRouter = FoldPath{}
+	_ PathingRouter = FoldNoExtPath{}
+)
+
+//FoldPath is a Path that ignores the case of the names in the request
+//path, so that /About and /ABOUT are routed as /about. Names are matched
+//by Unicode simple case folding, as by Fold, so the keys of a FoldPath
+//should be written folded: "about", not "About". Ampersands capture the
+//name as the request spelled it.
+//
+//A name spelled other than as its key is not canonical. By default it is
+//routed as the key is, but a RouteHandler with a PathPolicy of PathStrict
+//does not route it, and one with PathRedirect redirects to the path with
+//the key's spelling once the route is found.
+type FoldPath Path
+
+//FoldNoExtPath is a FoldPath that also ignores extensions, like NoExtPath.
+type FoldNoExtPath Path
+
+//Function Fold returns s case folded, as FoldPath matches names: each
+//rune is mapped to its lower case form, so that runes which fold together
+//("K", "k" and the Kelvin sign) map to the same one.
+func Fold(s string) string {
+	return strings.Map(func(r rune) rune {
+		return unicode.ToLower(unicode.ToUpper(r))
+	}, s)
+}
+
+//Function Child is provided by all types implementing the PathingRouter
+//interface.
+func (f FoldPath) Child(subpath string) (Router, string) {
+	return foldChild(Path(f), subpath, false)
+}
+
+func (f FoldPath) RouteHTTP(rq *http.Request) Router {
+	return PathRouteHTTP(f, rq)
+}
+
+//Function Child is provided by all types implementing the PathingRouter
+//interface.
+func (f FoldNoExtPath) Child(subpath string) (Router, string) {
+	return foldChild(Path(f), subpath, true)
+}
+
+func (f FoldNoExtPath) RouteHTTP(rq *http.Request) Router {
+	return PathRouteHTTP(f, rq)
+}
+
+func foldChild(p Path, subpath string, noExt bool) (Router, string) {
+	process := Fold
+	if noExt {
+		process = func(s string) string {
+			return Fold(strings.TrimSuffix(s, Pathp.Ext(s)))
+		}
+	}
+
+	r, remaining := p.ChildProcess(subpath, process)
+
+	//an exact key was matched, but maybe not spelled as it is
+	name := segment(subpath)
+	key := process(name)
+	if r == nil || isSpecial(key) || p[key] == nil {
+		return r, remaining
+	}
+	spelling := key
+	if noExt {
+		spelling += Pathp.Ext(name)
+	}
+	if spelling == name {
+		return r, remaining
+	}
+	return respelled{
+		Router: r,
+		from:   name,
+		to:     spelling,
+	}, remaining
+}
+
+//respelled is returned by the Child functions of FoldPaths in place of the
+//Router the path continues at, when the name matched is not spelled as its
+//key, so that PathRouteHTTP can record the canonical spelling.
+type respelled struct {
+	Router
+	from, to string
+}
+
+//Function respell records that the name from, followed in the path by
+//remaining, is spelled to in the canonical path of rq.
+func (s *state) respell(rq *http.Request, from, to, remaining string) {
+	if !s.respelled {
+		s.respelled, s.canonical = true, cleanPath(rq.URL.Path)
+	}
+	end := len(s.canonical) - len(remaining)
+	if remaining != "" {
+		//the slash before remaining
+		end--
+	}
+	start := end - len(from)
+	if start < 0 || s.canonical[start:end] != from {
+		return
+	}
+	s.canonical = s.canonical[:start] + to + s.canonical[end:]
+}
+
+//Function unspell records the canonical spelling of the name r respells,
+//if it is a respelled, and returns the Router r stands in for.
+func unspell(rq *http.Request, r Router, remaining string) Router {
+	if rs, ok := r.(respelled); ok {
+		stateOf(rq).respell(rq, rs.from, rs.to, remaining)
+		return rs.Router
+	}
+	return r
+}
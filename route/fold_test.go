@@ -0,0 +1,64 @@
+package route
+
+import (
+	"testing"
+)
+
+func TestFoldPath(t *testing.T) {
+	tree := FoldPath{
+		"about": paramsHandler(),
+		"users": FoldNoExtPath{
+			"&id": paramsHandler("id"),
+			"new": paramsHandler(),
+		},
+		"straße": paramsHandler(),
+	}
+
+	for _, c := range []struct {
+		policy   PathPolicy
+		url      string
+		code     int
+		location string
+		body     string
+	}{
+		{PathLenient, "http://example.com/About", 200, "", ""},
+		{PathLenient, "http://example.com/ABOUT", 200, "", ""},
+		{PathStrict, "http://example.com/about", 200, "", ""},
+		{PathStrict, "http://example.com/About", 404, "", ""},
+		{PathRedirect, "http://example.com/About?x=1", 301, "/about?x=1", ""},
+		{PathRedirect, "http://example.com/USERS/New.html", 301, "/users/new.html", ""},
+		{PathRedirect, "http://example.com/users/New", 301, "/users/new", ""},
+		{PathRedirect, "http://example.com/Users/Bob", 301, "/users/Bob", ""},
+		{PathRedirect, "http://example.com/users/Bob", 200, "", "id=Bob;"},
+		{PathLenient, "http://example.com/USERS/BobSmith.json", 200, "", "id=BobSmith.json;"},
+		{PathRedirect, "http://example.com/STRASSE", 404, "", ""},
+		{PathRedirect, "http://example.com/STRAßE", 301, "/stra%C3%9Fe", ""},
+		{PathRedirect, "http://example.com/nowhere", 404, "", ""},
+	} {
+		h := RouteHandler{
+			Router:   tree,
+			NotFound: NotFound,
+			Paths:    c.policy,
+		}
+		rw := serve(h, "GET", c.url)
+		if rw.Code != c.code || rw.Header().Get("Location") != c.location {
+			t.Errorf("%+v: got %d %q", c, rw.Code, rw.Header().Get("Location"))
+		}
+		if c.code == 200 && rw.Body.String() != c.body {
+			t.Errorf("%+v: got body %q", c, rw.Body.String())
+		}
+	}
+}
+
+func TestFold(t *testing.T) {
+	for in, expected := range map[string]string{
+		"About":       "about",
+		"\u212Aelvin": "kelvin",
+		"ΣΊΣΥΦΟΣ":     "σίσυφοσ",
+		"ſ":           "s",
+	} {
+		if got := Fold(in); got != expected {
+			t.Errorf("Fold(%+q): expected %+q, got %+q", in, expected, got)
+		}
+	}
+}
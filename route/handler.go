@@ -24,3 +24,22 @@ type Handler struct {
 func (h Handler) RouteHTTP(rq *http.Request) Router {
 	return h
 }
+
+//wrapped routes as its Router does, wrapping the http.Handler of the
+//Handler the route ends at with wrap.
+type wrapped struct {
+	Router
+	wrap func(http.Handler) http.Handler
+}
+
+func (w wrapped) RouteHTTP(rq *http.Request) Router {
+	if hl, ok := w.Router.(Handler); ok {
+		return Handle(w.wrap(hl.Handler))
+	}
+
+	next := w.Router.RouteHTTP(rq)
+	if next == nil {
+		return nil
+	}
+	return wrapped{next, w.wrap}
+}
@@ -0,0 +1,79 @@
+package route
+
+import (
+	"net/http"
+)
+
+var (
+	_ Router = Header{}
+	_ Router = Query{}
+	_ Router = Cookie{}
+)
+
+//Type Header is a Router which routes by the value of the request header
+//Name ("Api-Version"). The request is routed to the Router in Values
+//keyed by the value, or Default if there is none. A value found in Values
+//is captured under Name, so it can be had with Param.
+//
+//The response of the Handler routed to has Name added to its Vary header.
+type Header struct {
+	Name    string
+	Values  map[string]Router
+	Default Router
+}
+
+func (h Header) RouteHTTP(rq *http.Request) Router {
+	r := choose(rq, h, h.Name, rq.Header.Get(h.Name), h.Values, h.Default)
+	if r == nil {
+		return nil
+	}
+	return wrapped{r, vary(h.Name)}
+}
+
+//Type Query is a Header for the query parameter Name ("v" in ?v=2).
+//If the parameter is given more than once, the first value is used.
+type Query struct {
+	Name    string
+	Values  map[string]Router
+	Default Router
+}
+
+func (q Query) RouteHTTP(rq *http.Request) Router {
+	return choose(rq, q, q.Name, rq.URL.Query().Get(q.Name), q.Values, q.Default)
+}
+
+//Type Cookie is a Header for the value of the cookie Name. The response
+//of the Handler routed to has Cookie added to its Vary header.
+type Cookie struct {
+	Name    string
+	Values  map[string]Router
+	Default Router
+}
+
+func (c Cookie) RouteHTTP(rq *http.Request) Router {
+	var value string
+	if ck, err := rq.Cookie(c.Name); err == nil {
+		value = ck.Value
+	}
+	r := choose(rq, c, c.Name, value, c.Values, c.Default)
+	if r == nil {
+		return nil
+	}
+	return wrapped{r, vary("Cookie")}
+}
+
+//Function choose returns the Router in values keyed by value, capturing
+//value under name, or def if there is none.
+func choose(rq *http.Request, router Router, name, value string, values map[string]Router, def Router) Router {
+	if r, ok := values[value]; ok && r != nil {
+		s := stateOf(rq)
+		s.step(router, value, "", r)
+		s.capture(Capture{
+			Name:  name,
+			Value: value,
+		})
+		return r
+	}
+	peekState(rq).step(router, value, "", def)
+	return def
+}
@@ -0,0 +1,58 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHeader(t *testing.T) {
+	values := func() map[string]Router {
+		return map[string]Router{
+			"1": paramsHandler("Api-Version", "v", "session"),
+			"2": paramsHandler("Api-Version", "v", "session"),
+		}
+	}
+	h := RouteHandler{
+		Router: Path{
+			"header": Header{Name: "Api-Version", Values: values(), Default: paramsHandler("Api-Version")},
+			"query":  Query{Name: "v", Values: values()},
+			"cookie": Cookie{Name: "session", Values: values(), Default: paramsHandler("session")},
+		},
+		NotFound: NotFound,
+	}
+
+	for _, c := range []struct {
+		url, header, cookie string
+		expected, vary      string
+	}{
+		{"/header", "2", "", "Api-Version=2;v=;session=;", "Api-Version"},
+		{"/header", "3", "", "Api-Version=;", "Api-Version"},
+		{"/header", "", "", "Api-Version=;", "Api-Version"},
+		{"/query?v=1", "", "", "Api-Version=;v=1;session=;", ""},
+		{"/query?v=3", "", "", "404", ""},
+		{"/cookie", "", "1", "Api-Version=;v=;session=1;", "Cookie"},
+		{"/cookie", "", "x", "session=;", "Cookie"},
+	} {
+		rw := httptest.NewRecorder()
+		rq, _ := http.NewRequest("GET", "http://example.com"+c.url, nil)
+		if c.header != "" {
+			rq.Header.Set("Api-Version", c.header)
+		}
+		if c.cookie != "" {
+			rq.AddCookie(&http.Cookie{Name: "session", Value: c.cookie})
+		}
+		h.ServeHTTP(rw, rq)
+
+		got := rw.Body.String()
+		if rw.Code == http.StatusNotFound {
+			got = "404"
+		}
+		if got != c.expected {
+			t.Errorf("%s %+v: expected %q, got %q", c.url, c, c.expected, got)
+		}
+		if v := rw.Header().Get("Vary"); rw.Code == 200 && v != c.vary {
+			t.Errorf("%s %+v: expected Vary %q, got %q", c.url, c, c.vary, v)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package route
+
+import (
+	"fmt"
+	"github.com/TShadwell/fweight"
+	"net/http"
+	"strings"
+)
+
+var _ Router = make(Media)
+
+//Type Media is a Router which routes by the media types the request
+//accepts. It is keyed by media type ("text/html", "application/json"),
+//and the key with the highest quality in the Accept header of the request
+//is routed to, as in fweight.Quality. Ties go to the key that sorts first.
+//
+//The empty key ("") is the default, used if the request has no Accept
+//header, or none of the keys is acceptable. If there is no default,
+//a request without an Accept header is routed to the first key, and one
+//that accepts none of the keys to the NotAcceptable handler of the nearest
+//RouteHandler. If there is none, a short plain text message is served
+//with 406 Not Acceptable.
+//
+//The response of the Handler routed to has "Vary: Accept" set.
+type Media map[string]Router
+
+//Function Type sets the Router for the media type mt.
+func (m Media) Type(mt string, r Router) Media {
+	if m == nil {
+		m = make(Media)
+	}
+	m[mt] = r
+	return m
+}
+
+func (m Media) RouteHTTP(rq *http.Request) Router {
+	//nil Routers are as good as missing
+	all := sortedKeys(m)
+	keys := all[:0]
+	for _, k := range all {
+		if m[k] != nil {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	var (
+		key   string
+		found bool
+	)
+	if accept := rq.Header.Get("Accept"); accept == "" {
+		//the empty key sorts first
+		key, found = keys[0], true
+	} else {
+		ranges := fweight.ParseAccept(accept)
+		var best float64
+		for _, k := range keys {
+			if k == "" {
+				continue
+			}
+			if q := fweight.Quality(fweight.MediaType(k), ranges); q > best {
+				key, best, found = k, q, true
+			}
+		}
+		if !found {
+			found = m[""] != nil
+		}
+	}
+
+	if !found {
+		r := Handle(notAcceptable(rq, keys))
+		peekState(rq).step(m, "", "", r)
+		return r
+	}
+
+	peekState(rq).step(m, key, "", m[key])
+	return wrapped{m[key], vary("Accept")}
+}
+
+//Function vary returns a wrapper adding field to the Vary header of the
+//response.
+func vary(field string) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, rq *http.Request) {
+			rw.Header().Add("Vary", field)
+			h.ServeHTTP(rw, rq)
+		})
+	}
+}
+
+//Function notAcceptable returns the NotAcceptable handler of the nearest
+//RouteHandler, or a default for the media types available.
+func notAcceptable(rq *http.Request, available []string) http.Handler {
+	if s := peekState(rq); s != nil && s.notAcceptable != nil {
+		return s.notAcceptable
+	}
+	return http.HandlerFunc(func(rw http.ResponseWriter, rq *http.Request) {
+		rw.Header().Set("Content-Type", "text/plain;charset=utf8")
+		rw.WriteHeader(http.StatusNotAcceptable)
+		fmt.Fprintf(rw, "None of the media types accepted are available, only %s.", strings.Join(available, ", "))
+	})
+}
@@ -0,0 +1,61 @@
+package route
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMedia(t *testing.T) {
+	m := Media{
+		"text/html":        HandleFunc(func(rw http.ResponseWriter, rq *http.Request) { fmt.Fprint(rw, "html") }),
+		"application/json": HandleFunc(func(rw http.ResponseWriter, rq *http.Request) { fmt.Fprint(rw, "json") }),
+	}
+	h := RouteHandler{Router: m}
+
+	for accept, expected := range map[string]string{
+		"":                                     "json",
+		"text/html":                            "html",
+		"application/json":                     "json",
+		"text/*;q=0.5, application/json;q=0.4": "html",
+		"text/html;q=0.1, */*":                 "json",
+		"*/*, text/html;q=0":                   "json",
+		"image/png":                            "406",
+	} {
+		rw := httptest.NewRecorder()
+		rq, _ := http.NewRequest("GET", "http://example.com/", nil)
+		if accept != "" {
+			rq.Header.Set("Accept", accept)
+		}
+		h.ServeHTTP(rw, rq)
+
+		got := rw.Body.String()
+		if rw.Code == http.StatusNotAcceptable {
+			got = "406"
+		}
+		if got != expected {
+			t.Errorf("%+q: expected %q, got %q", accept, expected, got)
+		}
+		if rw.Code == 200 && rw.Header().Get("Vary") != "Accept" {
+			t.Errorf("%+q: Vary not set", accept)
+		}
+	}
+
+	m[""] = HandleFunc(func(rw http.ResponseWriter, rq *http.Request) { fmt.Fprint(rw, "default") })
+	rq, _ := http.NewRequest("GET", "http://example.com/", nil)
+	rq.Header.Set("Accept", "image/png")
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, rq)
+	if rw.Body.String() != "default" {
+		t.Errorf("expected the default, got %d %q", rw.Code, rw.Body)
+	}
+
+	//a nil default is no default
+	m[""] = nil
+	rw = httptest.NewRecorder()
+	h.ServeHTTP(rw, rq)
+	if rw.Code != http.StatusNotAcceptable {
+		t.Errorf("expected 406 for a nil default, got %d %q", rw.Code, rw.Body)
+	}
+}
@@ -0,0 +1,92 @@
+package route
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+var (
+	_ PathingRouter = Mount{}
+	_ Router        = Mount{}
+)
+
+//A Mount is a PathingRouter that ends the descent of the path trie at
+//its http.Handler, which is served the request with the part of the
+//path matched by the trie stripped from rq.URL.Path and rq.URL.RawPath,
+//as http.StripPrefix would. This lets a http.FileServer, pprof or another
+//mux be placed at any depth of a trie of Paths:
+//
+//	Path{
+//		"static": Mount{http.FileServer(http.Dir("static"))},
+//	}
+//
+//The path of the request as it was before any Mount stripped it is
+//given by OriginalPath.
+//
+//Only the part of the path matched by PathingRouters is stripped: a Mount
+//reached through a Verb, say, is served the path whole, just as a Path
+//would route it.
+type Mount struct {
+	http.Handler
+}
+
+func (m Mount) RouteHTTP(rq *http.Request) Router {
+	return PathRouteHTTP(m, rq)
+}
+
+//Function Child is provided by all types implementing the PathingRouter
+//interface. The remainder of subpath is what the Handler is served.
+func (m Mount) Child(subpath string) (Router, string) {
+	return Handle(stripped{
+		Handler: m.Handler,
+		rest:    strings.Trim(subpath, "/"),
+	}), ""
+}
+
+//stripped serves its Handler with the URL path of the request
+//replaced by rest.
+type stripped struct {
+	http.Handler
+	rest string
+}
+
+func (s stripped) ServeHTTP(rw http.ResponseWriter, rq *http.Request) {
+	if st := stateOf(rq); !st.mounted {
+		st.mounted, st.originalPath = true, rq.URL.Path
+	}
+
+	u := *rq.URL
+	u.Path, u.RawPath = "/"+s.rest, ""
+	if s.rest != "" && strings.HasSuffix(rq.URL.Path, "/") {
+		u.Path += "/"
+	}
+	if rq.URL.RawPath != "" {
+		u.RawPath = rawSuffix(rq.URL.EscapedPath(), u.Path)
+	}
+
+	r2 := new(http.Request)
+	*r2 = *rq
+	r2.URL = &u
+	s.Handler.ServeHTTP(rw, r2)
+}
+
+//Function rawSuffix returns the end of the escaped path escaped that
+//unescapes to path, or the empty string if there is none.
+func rawSuffix(escaped, path string) string {
+	for i := strings.LastIndex(escaped, "/"); i >= 0; i = strings.LastIndex(escaped[:i], "/") {
+		if p, err := url.PathUnescape(escaped[i:]); err == nil && p == path {
+			return escaped[i:]
+		}
+	}
+	return ""
+}
+
+//Function OriginalPath returns the URL path of rq as it was before any
+//Mount stripped it.
+func OriginalPath(rq *http.Request) string {
+	if s := peekState(rq); s != nil && s.mounted {
+		return s.originalPath
+	}
+	return rq.URL.Path
+}
@@ -0,0 +1,35 @@
+package route
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestMount(t *testing.T) {
+	mounted := Mount{http.HandlerFunc(func(rw http.ResponseWriter, rq *http.Request) {
+		fmt.Fprintf(rw, "%s %s %s", rq.URL.Path, rq.URL.RawPath, OriginalPath(rq))
+	})}
+	h := RouteHandler{
+		Router: Path{
+			"static": mounted,
+			"users": Path{
+				"&id": Name("files", mounted),
+			},
+		},
+		NotFound: NotFound,
+	}
+
+	for url, expected := range map[string]string{
+		"http://example.com/static":              "/  /static",
+		"http://example.com/static/":             "/  /static/",
+		"http://example.com/static/css/a.css":    "/css/a.css  /static/css/a.css",
+		"http://example.com/static/dir/":         "/dir/  /static/dir/",
+		"http://example.com/users/bob/a%2Fb/c":   "/a/b/c /a%2Fb/c /users/bob/a/b/c",
+		"http://example.com/users/bob/notes.txt": "/notes.txt  /users/bob/notes.txt",
+	} {
+		if got := serve(h, "GET", url).Body.String(); got != expected {
+			t.Errorf("%s: expected %q, got %q", url, expected, got)
+		}
+	}
+}
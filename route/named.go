@@ -0,0 +1,195 @@
+package route
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+var (
+	_ PathingRouter = Named{}
+	_ DomainRouter  = Named{}
+)
+
+//A Named Router is a Router with a name, that URL can build URLs
+//to. Named does not take part in routing: it descends into its Router
+//as part of any Path or Subdomain trie it is in.
+type Named struct {
+	Name string
+	Router
+}
+
+//Function Name returns r, named name.
+func Name(name string, r Router) Named {
+	return Named{
+		Name:   name,
+		Router: r,
+	}
+}
+
+func (n Named) RouteHTTP(rq *http.Request) Router {
+	return n.Router
+}
+
+//Function Child is provided by all types implementing the PathingRouter
+//interface.
+func (n Named) Child(subpath string) (Router, string) {
+	if p, ok := n.Router.(PathingRouter); ok {
+		return p.Child(subpath)
+	}
+	return n.Router, subpath
+}
+
+//Function Subdomain is provided by all types implementing the DomainRouter
+//interface.
+func (n Named) Subdomain(subpath string) (Router, string) {
+	if d, ok := n.Router.(DomainRouter); ok {
+		return d.Subdomain(subpath)
+	}
+	return n.Router, subpath
+}
+
+var ErrOddParams = errors.New("route: odd number of URL parameters")
+
+//An ErrNoRoute is returned by URL when there is no Router with the given name.
+type ErrNoRoute string
+
+func (e ErrNoRoute) Error() string {
+	return fmt.Sprintf("route: no Router named %+q", string(e))
+}
+
+//An ErrMissingParam is returned by URL when a parameter needed to
+//build a URL was not given, or is not acceptable.
+type ErrMissingParam struct {
+	Route, Param string
+}
+
+func (e ErrMissingParam) Error() string {
+	return fmt.Sprintf("route: %+q needs a value for parameter %+q", e.Route, e.Param)
+}
+
+//Function URL returns the URL that routes to the Router in the tree root
+//which is Named name. params are pairs of parameter names and values
+//("id", "42", ...), which fill in the named ampersands, wildcards and
+//Matchers on the way, and the wildcard labels of Subdomains. The Host of the URL is set if a Subdomain is on the way.
+//
+//If more than one Router has the same name, the first found is used.
+func URL(root Router, name string, params ...string) (*url.URL, error) {
+	if len(params)%2 != 0 {
+		return nil, ErrOddParams
+	}
+	values := make(map[string]string, len(params)/2)
+	for i := 0; i < len(params); i += 2 {
+		values[params[i]] = params[i+1]
+	}
+
+	var (
+		path, domain []hop
+		found        bool
+	)
+	descend(root, func(r Router, t trail) bool {
+		if found {
+			return false
+		}
+		if n, ok := r.(Named); ok && n.Name == name {
+			path, domain, found = t.path, t.domain, true
+		}
+		return !found
+	})
+	if !found {
+		return nil, ErrNoRoute(name)
+	}
+
+	u := new(url.URL)
+	var err error
+	if u.Host, err = buildHost(name, domain, values); err != nil {
+		return nil, err
+	}
+	if u.Path, u.RawPath, err = buildPath(name, path, values); err != nil {
+		return nil, err
+	}
+	if u.RawPath == u.Path {
+		u.RawPath = ""
+	}
+	return u, nil
+}
+
+func buildHost(route string, domain []hop, values map[string]string) (string, error) {
+	labels := make([]string, 0, len(domain))
+	for i := len(domain) - 1; i >= 0; i-- {
+		k := domain[i].key
+		switch {
+		case k == termHere:
+			continue
+		case isAmpersand(k):
+			v, ok := values[k[1:]]
+			if !ok || v == "" || strings.Contains(v, ".") {
+				return "", ErrMissingParam{route, k[1:]}
+			}
+			k = v
+		}
+		labels = append(labels, k)
+	}
+	return strings.Join(labels, "."), nil
+}
+
+func buildPath(route string, path []hop, values map[string]string) (p, raw string, err error) {
+	var segments, escaped []string
+	for _, h := range path {
+		var (
+			v     string
+			ok    bool
+			param string
+		)
+		switch {
+		case h.kind == matcherEdge:
+			param = h.key
+		case isAmpersand(h.key), isWildcard(h.key):
+			if param = h.key[1:]; param == "" {
+				param = h.key
+			}
+		case h.key == "":
+			continue
+		default:
+			segments = append(segments, h.key)
+			escaped = append(escaped, url.PathEscape(h.key))
+			continue
+		}
+
+		if v, ok = values[param]; !ok {
+			return "", "", ErrMissingParam{route, param}
+		}
+
+		switch {
+		case isWildcard(h.key):
+			v = strings.Trim(v, "/")
+			segments = append(segments, v)
+			for _, s := range strings.Split(v, "/") {
+				escaped = append(escaped, url.PathEscape(s))
+			}
+			continue
+		case h.kind == matcherEdge:
+			if _, ok = h.match.MatchSegment(v); !ok {
+				return "", "", ErrMissingParam{route, param}
+			}
+		}
+		segments = append(segments, v)
+		escaped = append(escaped, url.PathEscape(v))
+	}
+
+	return "/" + strings.Join(segments, "/"), "/" + strings.Join(escaped, "/"), nil
+}
+
+//Function URLFunc returns a function that calls URL on root and returns
+//the result as a string, for use in templates.
+func URLFunc(root Router) func(name string, params ...string) (string, error) {
+	return func(name string, params ...string) (string, error) {
+		u, err := URL(root, name, params...)
+		if err != nil {
+			return "", err
+		}
+		return u.String(), nil
+	}
+}
@@ -0,0 +1,77 @@
+package route
+
+import (
+	"fmt"
+	"testing"
+)
+
+func ExampleURL() {
+	tree := Subdomain{
+		"example.com": Subdomain{
+			"api": Path{
+				"users": Path{
+					"&id": Name("user", GetOnly(paramsHandler("id"))),
+				},
+			},
+		},
+	}
+
+	u, err := URL(tree, "user", "id", "bob smith")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(u)
+	// Output: //api.example.com/users/bob%20smith
+}
+
+func TestURL(t *testing.T) {
+	tree := Path{
+		"":       Name("index", paramsHandler()),
+		"static": Path{"*file": Name("static", paramsHandler("file"))},
+		"users": TypedPath{
+			Path: Path{"me": Name("me", paramsHandler())},
+		}.Match("id", Int, Name("user", paramsHandler())),
+		"admin": GetOnly(Path{
+			"admin": Path{"x": Name("reset", paramsHandler())},
+		}),
+	}
+
+	for _, c := range []struct {
+		name     string
+		params   []string
+		expected string
+	}{
+		{"index", nil, "/"},
+		{"me", nil, "/users/me"},
+		{"user", []string{"id", "42"}, "/users/42"},
+		{"static", []string{"file", "/css/site.css"}, "/static/css/site.css"},
+		{"reset", nil, "/admin/x"},
+	} {
+		//a Compiled builds URLs as the tree it was compiled from
+		for _, root := range []Router{tree, Compile(tree)} {
+			u, err := URL(root, c.name, c.params...)
+			if err != nil {
+				t.Errorf("%s: %s", c.name, err)
+				continue
+			}
+			if u.String() != c.expected {
+				t.Errorf("%s: expected %q, got %q", c.name, c.expected, u)
+			}
+		}
+	}
+
+	h := RouteHandler{Router: tree, NotFound: NotFound}
+	if rw := serve(h, "GET", "http://example.com/static/a/b"); rw.Code != 200 {
+		t.Errorf("Named Router did not route, got %d", rw.Code)
+	}
+
+	if _, err := URL(tree, "user", "id", "bob"); err == nil {
+		t.Error("expected an error for an unacceptable parameter")
+	}
+	if _, err := URL(tree, "static"); err == nil {
+		t.Error("expected an error for a missing parameter")
+	}
+	if _, err := URL(tree, "nothing"); err == nil {
+		t.Error("expected an error for a missing route")
+	}
+}
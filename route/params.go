@@ -0,0 +1,94 @@
+package route
+
+import (
+	"net/http"
+)
+
+//A Capture is a value recorded while routing a request, such as the path
+//segment swallowed by a named ampersand ("&id"). The Value is the segment
+//as the request spelled it, even when it was matched without its extension,
+//as by a NoExtPath.
+//
+//Parsed holds the value produced by the SegmentMatcher that accepted the
+//segment, if any.
+type Capture struct {
+	Name   string
+	Value  string
+	Parsed interface{}
+}
+
+//captured is returned by the Child functions of PathingRouters in place of
+//the Router the path continues at, so that the PathRouteHTTP walking the
+//trie can record the Capture against the request before continuing.
+//
+//If rest is set the capture swallowed the remainder of the path, and the
+//descent of the trie ends at Router.
+type captured struct {
+	Capture
+	Router
+	rest bool
+}
+
+//RouteHTTP records the capture when a captured is routed outside of
+//PathRouteHTTP, returning the Router it wraps.
+func (c captured) RouteHTTP(rq *http.Request) Router {
+	stateOf(rq).capture(c.Capture)
+	return c.Router
+}
+
+func (s *state) capture(c Capture) {
+	s.captures = append(s.captures, c)
+}
+
+//Function unwrap records any capture r carries and returns the Router it
+//stands in for, and whether the descent of the trie ends there.
+func unwrap(rq *http.Request, r Router) (Router, bool) {
+	if c, ok := r.(captured); ok {
+		stateOf(rq).capture(c.Capture)
+		return c.Router, c.rest
+	}
+	return r, false
+}
+
+//Function lookup returns the last capture named name, or nil.
+func lookup(rq *http.Request, name string) *Capture {
+	s := peekState(rq)
+	if s == nil {
+		return nil
+	}
+	for i := len(s.captures) - 1; i >= 0; i-- {
+		if s.captures[i].Name == name {
+			return &s.captures[i]
+		}
+	}
+	return nil
+}
+
+//Function Param returns the value captured under name while routing rq,
+//or the empty string. If name was captured more than once, as can happen
+//when Paths are nested, the capture nearest the end of the route wins.
+func Param(rq *http.Request, name string) string {
+	if c := lookup(rq, name); c != nil {
+		return c.Value
+	}
+	return ""
+}
+
+//Function ParsedParam returns the parsed value captured under name while
+//routing rq, or nil. See Param.
+func ParsedParam(rq *http.Request, name string) interface{} {
+	if c := lookup(rq, name); c != nil {
+		return c.Parsed
+	}
+	return nil
+}
+
+//Function Params returns every value captured while routing rq, in the
+//order they were captured.
+func Params(rq *http.Request) []Capture {
+	s := peekState(rq)
+	if s == nil {
+		return nil
+	}
+	return append([]Capture(nil), s.captures...)
+}
@@ -0,0 +1,120 @@
+package route
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func paramsHandler(names ...string) Handler {
+	return HandleFunc(func(rw http.ResponseWriter, rq *http.Request) {
+		for _, n := range names {
+			fmt.Fprintf(rw, "%s=%s;", n, Param(rq, n))
+		}
+	})
+}
+
+func serve(h http.Handler, method, url string) *httptest.ResponseRecorder {
+	rw := httptest.NewRecorder()
+	rq, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		panic(err)
+	}
+	h.ServeHTTP(rw, rq)
+	return rw
+}
+
+func TestParam(t *testing.T) {
+	h := RouteHandler{
+		Router: Subdomain{
+			"example.com": Path{
+				"user": Path{
+					"&user": Path{
+						"": paramsHandler("user"),
+						"files": NoExtPath{
+							"&file": paramsHandler("user", "file"),
+						},
+					},
+				},
+			},
+		},
+		NotFound: NotFound,
+		Recover:  HandleRecovery,
+	}
+
+	for url, expected := range map[string]string{
+		"http://example.com/user/bob":                 "user=bob;",
+		"http://example.com/user/anne/":               "user=anne;",
+		"http://example.com/user/anne/files/cat.png":  "user=anne;file=cat.png;",
+		"http://example.com/user/anne/files/notes.tx": "user=anne;file=notes.tx;",
+		//a segment spelled like a special key is still captured
+		"http://example.com/user/&user":            "user=&user;",
+		"http://example.com/user/anne/files/&file": "user=anne;file=&file;",
+	} {
+		rw := serve(h, "GET", url)
+		if rw.Code != http.StatusOK || rw.Body.String() != expected {
+			t.Errorf("%s: expected %q, got %d %q", url, expected, rw.Code, rw.Body.String())
+		}
+	}
+
+	//the radix tree captures segments as the trie does
+	files := Compile(Path{
+		"files": NoExtPath{
+			"&file": paramsHandler("file"),
+		},
+	})
+	for url, expected := range map[string]string{
+		"http://example.com/files/cat.png":       "file=cat.png;",
+		"http://example.com/files/report.v2.pdf": "file=report.v2.pdf;",
+	} {
+		rw := serve(RouteHandler{Router: files}, "GET", url)
+		if rw.Code != http.StatusOK || rw.Body.String() != expected {
+			t.Errorf("compiled %s: expected %q, got %d %q", url, expected, rw.Code, rw.Body.String())
+		}
+	}
+}
+
+func ExampleParam() {
+	h := RouteHandler{
+		Router: Path{
+			"user": Path{
+				"&name": HandleFunc(func(rw http.ResponseWriter, rq *http.Request) {
+					fmt.Println(Param(rq, "name"))
+				}),
+			},
+		},
+	}
+
+	rq, _ := http.NewRequest("GET", "http://example.com/user/bob", nil)
+	h.ServeHTTP(httptest.NewRecorder(), rq)
+	// Output: bob
+}
+
+func TestWildcard(t *testing.T) {
+	h := RouteHandler{
+		Router: Path{
+			"static": Path{
+				"*file": paramsHandler("file"),
+			},
+			"legacy": Path{
+				"*":   paramsHandler("*"),
+				"new": paramsHandler("*"),
+			},
+		},
+		NotFound: NotFound,
+	}
+
+	for url, expected := range map[string]string{
+		"http://example.com/static/css/site.css": "file=css/site.css;",
+		"http://example.com/static/":             "file=;",
+		"http://example.com/legacy/new":          "*=;",
+		"http://example.com/legacy/one":          "*=one;",
+		"http://example.com/legacy/one/two":      "*=one/two;",
+	} {
+		rw := serve(h, "GET", url)
+		if rw.Code != http.StatusOK || rw.Body.String() != expected {
+			t.Errorf("%s: expected %q, got %d %q", url, expected, rw.Code, rw.Body.String())
+		}
+	}
+}
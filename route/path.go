@@ -1,20 +1,25 @@
 package route
 
 import (
-	"log"
 	"net/http"
 	Pathp "path"
-	"reflect"
 	"strings"
 )
 
 func isSpecial(s string) bool {
-	switch s {
-	case "&", "":
-		return true
-	default:
-		return false
-	}
+	return s == "" || isAmpersand(s) || isWildcard(s)
+}
+
+//Function isAmpersand reports whether s is an ampersand key, plain ("&")
+//or named ("&id").
+func isAmpersand(s string) bool {
+	return len(s) > 0 && s[0] == '&'
+}
+
+//Function isWildcard reports whether s is a wildcard key, plain ("*") or
+//named ("*file").
+func isWildcard(s string) bool {
+	return len(s) > 0 && s[0] == '*'
 }
 
 //A PathingRouter is part of the filepath, and can take part
@@ -58,6 +63,16 @@ func (i NoExtPath) RouteHTTP(r *http.Request) Router {
 //Router, the one used if the path stops here, and the ampersand ("&") path
 //swallows the next file segment in the path, regardless of its contents.
 //
+//A named ampersand ("&id") swallows a segment in the same way, but records
+//it against the request so that it can later be retrieved with
+//Param(rq, "id"). A Path should have at most one ampersand key.
+//
+//The wildcard ("*") path swallows the whole of the remaining path, and ends
+//the descent of the trie there, even if its Router is a PathingRouter. The
+//swallowed path is recorded under "*", or under file for a named wildcard
+//("*file"). The wildcard is tried after the ampersand, and is also used
+//if the path terminates at a Path which has no empty name.
+//
 //It should be noted that when RouteHTTP is called
 //the PathRouter is followed to completion from the
 //start to end of the URL, thus using two routers separately
@@ -103,27 +118,47 @@ func PathRouteHTTP(p PathingRouter, rq *http.Request) Router {
 	/*
 		Now like SubdomainRouter!
 	*/
-	var (
-		currentPathingRouter PathingRouter = p
-		path                 string        = strings.Trim(rq.URL.Path, "/")
-		currentRouter        Router
-	)
+	return pathDescend(p, rq, cleanPath(rq.URL.Path))
+}
 
+//Function cleanPath returns the URL path urlPath as the PathingRouters
+//see it, with no leading or trailing slashes.
+func cleanPath(urlPath string) (path string) {
 	//fix paths
-	path = Pathp.Clean(path)
+	path = Pathp.Clean(strings.Trim(urlPath, "/"))
 
 	//We break spec a bit to allow direcories called "."
 	if path == "." {
 		path = ""
 	}
+	return
+}
+
+//Function pathDescend descends the trie of PathingRouters from p,
+//routing path.
+func pathDescend(p PathingRouter, rq *http.Request, path string) Router {
+	var (
+		currentPathingRouter PathingRouter = p
+		currentRouter        Router
+	)
+	s := peekState(rq)
 	for {
+		tried := path
 		currentRouter, path = currentPathingRouter.Child(path)
+		currentRouter = unspell(rq, currentRouter, path)
+
+		var rest bool
+		currentRouter, rest = unwrap(rq, currentRouter)
+		s.step(currentPathingRouter, segment(tried), path, currentRouter)
+		if _, ok := currentPathingRouter.(Mount); ok && strings.Trim(tried, "/") != "" {
+			stateOf(rq).handedOn = true
+		}
+		if rest {
+			break
+		}
 
 		var ok bool
 		if currentPathingRouter, ok = currentRouter.(PathingRouter); !ok {
-			if debug {
-				log.Printf("[-] Path routing terminated at %v [%v] and pathRouter %v [%v]", currentRouter, reflect.TypeOf(currentRouter), currentPathingRouter, reflect.TypeOf(currentPathingRouter))
-			}
 			break
 		}
 
@@ -143,6 +178,12 @@ func (p Path) Child(subpath string) (n Router, remainingSubpath string) {
 	return p.ChildProcess(subpath, nil)
 }
 
+//Function segment returns the first segment of subpath.
+func segment(subpath string) string {
+	_, s := swallowOne(strings.TrimLeft(subpath, "/"))
+	return s
+}
+
 func swallowOne(subpath string) (subsectpath, swallowed string) {
 	slashPos := strings.Index(subpath, "/")
 	if slashPos == -1 {
@@ -169,32 +210,25 @@ func (p Path) ChildProcess(subpath string, process func(string) string) (n Route
 		}
 	}
 
-	if debug {
-		log.Printf("[?] Currently in path %+v, with requested path %+q \n", p, subpath)
-	}
-
 	//strip leading slashes
 	subpath = strings.TrimLeft(subpath, "/")
 
 	//If strings.SplitN(subpath, "/", 3)'s length is two, then this
 	//is the only item left in the path, and thus we must terminate here.
 	if subpath == "" || subpath == "/" {
-		if debug {
-			log.Println("[?] Routing into current level (path is empty).")
+		if r := p[""]; r != nil {
+			return r, ""
 		}
-		return p[""], ""
+		return p.rest(subpath)
 	}
 
 	remaining, popped := swallowOne(subpath)
 
-	if debug {
-		log.Printf("If we chop one off, we get %s, %s\n", remaining, popped)
-	}
-
-	if pathRouter, ok := p[process(popped)]; ok {
-		return pathRouter, remaining
-	} else if debug {
-		log.Printf("%s was not present.\n", process(popped))
+	//special keys are not names, so "&id" is not matched by /&id
+	if key := process(popped); !isSpecial(key) {
+		if pathRouter, ok := p[key]; ok {
+			return pathRouter, remaining
+		}
 	}
 
 	//Check if we have a route that begins with the subpath
@@ -221,16 +255,80 @@ func (p Path) ChildProcess(subpath string, process func(string) string) (n Route
 		//I actually have no idea what this does
 	*/
 
-	if p["&"] != nil {
-		return p["&"], remaining
-	} else if debug {
-		log.Printf("[?] No ampersand present in Path, no swallow.")
+	if name, r := p.ampersand(); r != nil {
+		if name == "" {
+			return r, remaining
+		}
+		return captured{
+			Capture: Capture{
+				Name:  name,
+				Value: popped,
+			},
+			Router: r,
+		}, remaining
+	}
+
+	if r, remaining := p.rest(subpath); r != nil {
+		return r, remaining
 	}
 
 	//Not Found.
 	return nil, subpath
 }
 
+//Function rest routes subpath to the wildcard of this Path, if present.
+func (p Path) rest(subpath string) (Router, string) {
+	name, r := p.wildcard()
+	if r == nil {
+		return nil, subpath
+	}
+	if name == "" {
+		name = "*"
+	}
+	return captured{
+		Capture: Capture{
+			Name:  name,
+			Value: subpath,
+		},
+		Router: r,
+		rest:   true,
+	}, ""
+}
+
+//Function ampersand returns the ampersand Router of this Path and the
+//name it captures under, which is empty for the plain ampersand.
+func (p Path) ampersand() (name string, r Router) {
+	return ampersand(p)
+}
+
+//Function ampersand returns the Router of the ampersand key of m.
+//Subdomain uses the same keys for wildcard labels.
+func ampersand(m map[string]Router) (name string, r Router) {
+	if r = m["&"]; r != nil {
+		return
+	}
+	for k, v := range m {
+		if isAmpersand(k) && v != nil {
+			return k[1:], v
+		}
+	}
+	return
+}
+
+//Function wildcard returns the wildcard Router of this Path and the
+//name it captures under, which is empty for the plain wildcard.
+func (p Path) wildcard() (name string, r Router) {
+	if r = p["*"]; r != nil {
+		return
+	}
+	for k, v := range p {
+		if isWildcard(k) && v != nil {
+			return k[1:], v
+		}
+	}
+	return
+}
+
 //Returns s up until a char in terminators, or the whole string.
 func TrimPast(s, terminators string) string {
 	pos := strings.IndexAny(s, terminators)
@@ -256,6 +354,9 @@ func TrimPastRune(s string, r rune) string {
 //by an ampersand path. To achieve this, `prefix` is trimmed from the
 //beginning of the url, and the resulting string is returned up
 //until an instance of a char in `terminators` or the end of the string.
+//
+//A named ampersand, read back with Param, does not depend on where in the
+//URL the Path is mounted.
 func Ampersand(url, prefix, terminators string) string {
 	return TrimPast(strings.TrimPrefix(url, prefix), terminators)
 }
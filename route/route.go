@@ -4,7 +4,6 @@ import (
 	"net/http"
 )
 
-const debug = false
 const failOnPanic = false
 
 type (
@@ -14,6 +14,10 @@ When the RouteHandler is unable to find an http.Handler, or
 encounters an error while handling a response, it uses the NotFound
 and InternalServerError handlers.
 
+A Handler can also panic with a value implementing fweight.HTTPStatus,
+such as fweight.Err(fweight.StatusForbidden), to have the response handled
+by the StatusHandlers. See ServeHTTP.
+
 These functions run at the position of this RouteHandler in the pipeline,
 meaning compression and security middleware that wraps this http.Handler
 will still be executed.
@@ -24,12 +28,52 @@ type RouteHandler struct {
 	//Recover is called when there is a panic in a Router.
 	NotFound http.Handler
 	Recover  RecoverHandler
+
+	//StatusHandlers handle panics with values implementing
+	//fweight.HTTPStatus, by their status. They are expected to write
+	//the status themselves. The panic value is given by Recovered.
+	StatusHandlers map[fweight.Status]http.Handler
+
+	//Options and MethodNotAllowed are used by the Verbs in the tree,
+	//unless a RouteHandler nearer to the Verb in the tree sets its own.
+	//See Verb.RouteHTTP.
+	Options          VerbHandler
+	MethodNotAllowed VerbHandler
+
+	//NotAcceptable is used by the Media Routers in the tree in the
+	//same way, when none of the media types of a Media is acceptable.
+	NotAcceptable http.Handler
+
+	//Paths is what is done with requests whose URL path is not
+	//canonical, and TrailingSlash whether the canonical path ends in
+	//a slash. See PathPolicy.
+	Paths         PathPolicy
+	TrailingSlash bool
+
+	//Tracer, if set, traces the routing of the requests it enables,
+	//unless a RouteHandler above this one in the tree already has.
+	Tracer *Tracer
 }
 
 /*
 	NotFound is a convenience http.Handler for RouteHandler's NotFound.
 */
 var NotFound http.Handler = http.HandlerFunc(func(rw http.ResponseWriter, rq *http.Request) {
+	rw.Header().Add("Content-Type", "text/plain")
+	rw.WriteHeader(int(fweight.StatusNotFound))
+	fmt.Fprintf(
+		rw,
+		`A resource could not be found to match your request.
+	Request URI: %+q`,
+		rq.Host+rq.URL.Path,
+	)
+})
+
+/*
+	DebugNotFound is NotFound for development builds, which also writes
+	the request's headers, cookies included, and remote address.
+*/
+var DebugNotFound http.Handler = http.HandlerFunc(func(rw http.ResponseWriter, rq *http.Request) {
 	rw.Header().Add("Content-Type", "text/plain")
 	rw.WriteHeader(int(fweight.StatusNotFound))
 	fmt.Fprintf(
@@ -65,14 +109,29 @@ var HandleRecovery RecoverHandler = RecoverHandlerFunc(func(i interface{}) http.
 			fmt.Sprint(i),
 		)
 
-		log.Printf(
-			"Internal Server Error: %+q\n %s",
-			fmt.Sprint(i),
-			deb.Stack(),
-		)
+		logPanic(i)
 	})
 })
 
+//Function logPanic logs the value i the server panicked with, and the stack.
+func logPanic(i interface{}) {
+	log.Printf(
+		"Internal Server Error: %+q\n %s",
+		fmt.Sprint(i),
+		deb.Stack(),
+	)
+}
+
+//Function statusText returns a http.Handler that replies with status and
+//its text, in plain text.
+func statusText(status fweight.Status) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, rq *http.Request) {
+		rw.Header().Set("Content-Type", "text/plain;charset=utf8")
+		rw.WriteHeader(int(status))
+		fmt.Fprint(rw, status)
+	})
+}
+
 type (
 	RecoverHandler interface {
 		//'i' is the data the server paniced with.
@@ -88,33 +147,149 @@ func (r RecoverHandlerFunc) ServeRecover(i interface{}) http.Handler {
 
 func (r RouteHandler) HandleNotFound(rq *http.Request) http.Handler {
 	if r.NotFound == nil {
+		if h := r.StatusHandlers[fweight.StatusNotFound]; h != nil {
+			return h
+		}
 		panic(fweight.Err(fweight.StatusNotFound))
 	}
 	return r.NotFound
 }
 
+//Function HandleInternalServerError returns the http.Handler for the panic
+//i, from Recover. If Recover is nil, the StatusHandler for
+//500 Internal Server Error is used, with the panic and stack logged, or
+//if there is none a short plain text message is served.
 func (r RouteHandler) HandleInternalServerError(i interface{}) http.Handler {
-	if r.HandleInternalServerError == nil {
-		panic(fweight.Err(fweight.StatusInternalServerError))
+	if r.Recover == nil {
+		logPanic(i)
+		if h := r.StatusHandlers[fweight.StatusInternalServerError]; h != nil {
+			return h
+		}
+		return statusText(fweight.StatusInternalServerError)
 	}
 	return r.Recover.ServeRecover(i)
 }
 
+//Function HandlePanic returns the http.Handler for the panic i. Values
+//implementing fweight.HTTPStatus are handled by the StatusHandler for their
+//status, or for 404 Not Found by NotFound, without the stack being logged
+//unless the status is 500 or above. Other values, and 500 Internal Server
+//Error with no handler, are handled by HandleInternalServerError. Any other
+//status with no handler is answered with its own code and a short plain
+//text message, the stack being logged if it is 500 or above.
+//
+//The Header of a *fweight.Problem is sent with the response, whichever
+//handles it.
+func (r RouteHandler) HandlePanic(i interface{}) http.Handler {
+	hs, ok := i.(fweight.HTTPStatus)
+	if !ok {
+		return r.HandleInternalServerError(i)
+	}
+	return problemHeader(i, r.handleStatus(i, hs.HTTPStatusCode()))
+}
+
+func (r RouteHandler) handleStatus(i interface{}, status fweight.Status) http.Handler {
+	if h := r.StatusHandlers[status]; h != nil {
+		if status >= 500 {
+			logPanic(i)
+		}
+		return h
+	}
+
+	switch {
+	case status == fweight.StatusInternalServerError:
+		return r.HandleInternalServerError(i)
+	case status >= 500:
+		logPanic(i)
+	case status == fweight.StatusNotFound && r.NotFound != nil:
+		return r.NotFound
+	}
+	return statusText(status)
+}
+
+//Function problemHeader returns h, adding the Header of i to the response
+//first if i is a *fweight.Problem.
+func problemHeader(i interface{}, h http.Handler) http.Handler {
+	p, ok := i.(*fweight.Problem)
+	if !ok || len(p.Header) == 0 {
+		return h
+	}
+	return http.HandlerFunc(func(rw http.ResponseWriter, rq *http.Request) {
+		for k, v := range p.Header {
+			rw.Header()[k] = append(rw.Header()[k], v...)
+		}
+		h.ServeHTTP(rw, rq)
+	})
+}
+
+//Function Recovered returns the value a Handler of the tree rq was routed
+//through panicked with, when called by the http.Handler that handles it.
+func Recovered(rq *http.Request) interface{} {
+	if s := peekState(rq); s != nil {
+		return s.recovered
+	}
+	return nil
+}
+
+//Function enter records the configuration of this RouteHandler that is
+//used further down the tree against rq.
+func (r RouteHandler) enter(rq *http.Request) {
+	s := stateOf(rq)
+	if r.Options != nil {
+		s.options = r.Options
+	}
+	if r.MethodNotAllowed != nil {
+		s.notAllowed = r.MethodNotAllowed
+	}
+	if r.NotAcceptable != nil {
+		s.notAcceptable = r.NotAcceptable
+	}
+	if r.Paths != PathLenient {
+		s.paths, s.trailingSlash = r.Paths, r.TrailingSlash
+	}
+	if r.Tracer != nil && s.tracer == nil && r.Tracer.Enabled != nil && r.Tracer.Enabled(rq) {
+		s.tracer = r.Tracer
+	}
+	s.step(r, rq.Host+rq.URL.Path, "", r.Router)
+}
+
+//RouteHTTP routes rq through this RouteHandler's Router tree. This allows
+//RouteHandlers to be nested within a tree, configuring the part of the tree
+//below them.
+func (r RouteHandler) RouteHTTP(rq *http.Request) Router {
+	r.enter(rq)
+	return r.Router.RouteHTTP(rq)
+}
+
 /*
 	ServeHTTP traverses this RouteHandler's Router tree.
-	If a failure is encountered, if RouteHandler.Failure is non-nil, it
-	is called for an http.Handler which it uses to handle the response,
-	Handling at this RouteHandler's position in the middleware stack.
-	If it is nil, ServeHTTP instead panics with an ExtendedErr.
+	If no route is found, the request is handled by HandleNotFound. If the
+	Handler the route ends at panics, the response is handled by
+	HandlePanic, at this RouteHandler's position in the middleware stack.
 */
 func (s RouteHandler) ServeHTTP(rw http.ResponseWriter, rq *http.Request) {
+	//only the outermost RouteHandler logs the trace
+	outermost := peekState(rq) == nil
+	rq = withState(rq)
+	s.enter(rq)
+	st := stateOf(rq)
+	if outermost {
+		defer st.logTrace(rq)
+	}
+
 	for router := s.Router.RouteHTTP(rq); ; router = router.RouteHTTP(rq) {
 
+		//the path is checked once the route is found
+		if _, ok := router.(Handler); ok {
+			if r, ok := st.checkPath(rq); ok {
+				st.paths = PathLenient
+				router = r
+			}
+		}
+
 		//If we have a nil router, serve a 404.
 		if router == nil {
-			if debug {
-				fmt.Println("[!] Routing failed. Serving 404.")
-			}
+			st.sendTrace(rw)
 			s.HandleNotFound(rq).ServeHTTP(rw, rq)
 			return
 
@@ -126,16 +301,15 @@ func (s RouteHandler) ServeHTTP(rw http.ResponseWriter, rq *http.Request) {
 			if !failOnPanic {
 				defer func() {
 					if e := recover(); e != nil {
-						if debug {
-							fmt.Printf("[!] Recovered from panic %v\n", e)
-						}
-						s.HandleInternalServerError(e).ServeHTTP(rw, rq)
+						st.recovered = e
+						s.HandlePanic(e).ServeHTTP(rw, rq)
 					}
 					return
 				}()
 			}
 
 			//serve the response.
+			st.sendTrace(rw)
 			hl.ServeHTTP(rw, rq)
 			return
 		}
@@ -0,0 +1,62 @@
+package route
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/TShadwell/fweight"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+)
+
+func panics(i interface{}) Handler {
+	return HandleFunc(func(rw http.ResponseWriter, rq *http.Request) {
+		panic(i)
+	})
+}
+
+func TestHandlePanic(t *testing.T) {
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	tree := Path{
+		"forbidden":   panics(fweight.Err(fweight.StatusForbidden)),
+		"conflict":    panics(fweight.Err(fweight.StatusConflict)),
+		"missing":     panics(fweight.Err(fweight.StatusNotFound)),
+		"unavailable": panics(fweight.Err(fweight.StatusServiceUnavailable)),
+		"broken":      panics("broken"),
+	}
+	forbidden := http.HandlerFunc(func(rw http.ResponseWriter, rq *http.Request) {
+		rw.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(rw, "forbidden: ", Recovered(rq))
+	})
+
+	for _, c := range []struct {
+		h      RouteHandler
+		path   string
+		code   int
+		body   string
+		logged bool
+	}{
+		{RouteHandler{StatusHandlers: map[fweight.Status]http.Handler{403: forbidden}}, "forbidden", 403, "forbidden: Forbidden", false},
+		{RouteHandler{}, "conflict", 409, "Conflict", false},
+		{RouteHandler{NotFound: NotFound}, "missing", 404, "A resource could not be found", false},
+		{RouteHandler{Recover: HandleRecovery}, "unavailable", 503, "Service Unavailable", true},
+		{RouteHandler{StatusHandlers: map[fweight.Status]http.Handler{503: statusText(503)}}, "unavailable", 503, "Service Unavailable", true},
+		{RouteHandler{Recover: HandleRecovery}, "broken", 500, "An Internal Server Error", true},
+		{RouteHandler{}, "broken", 500, "Internal Server Error", true},
+	} {
+		logged.Reset()
+		c.h.Router = tree
+		rw := serve(c.h, "GET", "http://example.com/"+c.path)
+		if rw.Code != c.code || !strings.HasPrefix(rw.Body.String(), c.body) {
+			t.Errorf("%s: expected %d %q, got %d %q", c.path, c.code, c.body, rw.Code, rw.Body)
+		}
+		if stack := strings.Contains(logged.String(), "goroutine"); stack != c.logged {
+			t.Errorf("%s: expected the stack to be logged: %v, got %q", c.path, c.logged, logged.String())
+		}
+	}
+}
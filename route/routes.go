@@ -0,0 +1,151 @@
+package route
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/tabwriter"
+)
+
+//A Route describes one of the routes through a tree of Routers, ending at
+//a Router that has no children: usually a Handler.
+//
+//Host and Path are patterns built from the keys on the way, with named
+//ampersands and wildcards left as they are ("/users/&id") and the Matchers
+//of a TypedPath shown in braces ("/users/{id}"). An empty Host matches any
+//host, and an empty Method any method. Name is that of the nearest Named
+//on the way. Conditions lists anything else the route depends on, like
+//the media types of a Media ("Accept: text/html").
+//
+//A Mount is shown with a wildcard at the end of its Path ("/static/*").
+//
+//Opaque is set if the Router the route ends at is not a Handler, such as
+//a RouterFunc, because the Routers it leads to are only known once a
+//request is made.
+type Route struct {
+	Host       string   `json:"host"`
+	Path       string   `json:"path"`
+	Method     string   `json:"method"`
+	Conditions []string `json:"conditions,omitempty"`
+	Name       string   `json:"name,omitempty"`
+	Handler    string   `json:"handler"`
+	Opaque     bool     `json:"opaque"`
+	Router     Router   `json:"-"`
+}
+
+func hostPattern(domain []hop) string {
+	labels := make([]string, 0, len(domain))
+	for i := len(domain) - 1; i >= 0; i-- {
+		if domain[i].key != termHere {
+			labels = append(labels, domain[i].key)
+		}
+	}
+	return strings.Join(labels, ".")
+}
+
+func pathPattern(path []hop) string {
+	segments := make([]string, 0, len(path))
+	for _, h := range path {
+		switch {
+		case h.kind == matcherEdge:
+			segments = append(segments, "{"+h.key+"}")
+		case h.key != "":
+			segments = append(segments, h.key)
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+//Function Walk calls fn for each Route through the tree r, in a stable order.
+//If fn returns an error the walk stops, and Walk returns it.
+func Walk(r Router, fn func(Route) error) (err error) {
+	descend(r, func(r Router, t trail) bool {
+		if err != nil {
+			return false
+		}
+		if r == nil || walkable(r) {
+			return true
+		}
+
+		rt := Route{
+			Host:       hostPattern(t.domain),
+			Path:       pathPattern(t.path),
+			Method:     t.method,
+			Conditions: t.conditions,
+			Name:       t.name,
+			Router:     r,
+		}
+		switch h := r.(type) {
+		case Handler:
+			rt.Handler = fmt.Sprintf("%T", h.Handler)
+		case Mount:
+			//the Handler is served the rest of the path
+			rt.Path = strings.TrimSuffix(rt.Path, "/") + "/*"
+			rt.Handler = fmt.Sprintf("%T", h.Handler)
+		default:
+			rt.Handler = fmt.Sprintf("%T", r)
+			rt.Opaque = true
+		}
+
+		err = fn(rt)
+		return err == nil
+	})
+	return
+}
+
+//Function Routes returns every Route through the tree r, in the order
+//Walk visits them.
+func Routes(r Router) (routes []Route) {
+	Walk(r, func(rt Route) error {
+		routes = append(routes, rt)
+		return nil
+	})
+	return
+}
+
+//Function WriteRoutes writes routes to w as a table.
+func WriteRoutes(w io.Writer, routes []Route) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "HOST\tPATH\tMETHOD\tCONDITIONS\tNAME\tHANDLER")
+	for _, rt := range routes {
+		host, method, handler := rt.Host, rt.Method, rt.Handler
+		if host == "" {
+			host = "*"
+		}
+		if method == "" {
+			method = "*"
+		}
+		if rt.Opaque {
+			handler += " (opaque)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", host, rt.Path, method, strings.Join(rt.Conditions, ", "), rt.Name, handler)
+	}
+	return tw.Flush()
+}
+
+//Function RoutesHandler returns a Handler that lists the Routes of the tree r,
+//for debugging. The list is written as a table, or as JSON if the request
+//accepts application/json or has the query ?format=json.
+//
+//The tree is walked on each request, so the Handler can be mounted within
+//the tree it describes.
+func RoutesHandler(r Router) Handler {
+	return HandleFunc(func(rw http.ResponseWriter, rq *http.Request) {
+		routes := Routes(r)
+		if rq.URL.Query().Get("format") == "json" ||
+			strings.Contains(rq.Header.Get("Accept"), "application/json") {
+			rw.Header().Set("Content-Type", "application/json;charset=utf8")
+			if err := json.NewEncoder(rw).Encode(routes); err != nil {
+				panic(err)
+			}
+			return
+		}
+
+		rw.Header().Set("Content-Type", "text/plain;charset=utf8")
+		if err := WriteRoutes(rw, routes); err != nil {
+			panic(err)
+		}
+	})
+}
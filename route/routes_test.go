@@ -0,0 +1,41 @@
+package route
+
+import (
+	"net/http"
+	"os"
+)
+
+func ExampleRoutes() {
+	tree := Subdomain{
+		"example.com": Path{
+			"": GetOnly(paramsHandler()),
+			"users": Path{
+				"&id": Name("user", Verb{}.Get(paramsHandler()).Post(paramsHandler())),
+			},
+			"api": Header{
+				Name:    "Api-Version",
+				Values:  map[string]Router{"2": paramsHandler()},
+				Default: paramsHandler(),
+			},
+			"static": Mount{http.NotFoundHandler()},
+			"legacy": RouterFunc(func(rq *http.Request) Router { return nil }),
+			"feed": Media{
+				"":                 paramsHandler(),
+				"application/json": paramsHandler(),
+			},
+		},
+	}
+
+	WriteRoutes(os.Stdout, Routes(tree))
+	// Output:
+	// HOST         PATH        METHOD  CONDITIONS                NAME  HANDLER
+	// example.com  /           GET                                     http.HandlerFunc
+	// example.com  /api        *       Api-Version: 2                  http.HandlerFunc
+	// example.com  /api        *       Api-Version (default)           http.HandlerFunc
+	// example.com  /feed       *       Accept (default)                http.HandlerFunc
+	// example.com  /feed       *       Accept: application/json        http.HandlerFunc
+	// example.com  /legacy     *                                       route.RouterFunc (opaque)
+	// example.com  /static/*   *                                       http.HandlerFunc
+	// example.com  /users/&id  GET                               user  http.HandlerFunc
+	// example.com  /users/&id  POST                              user  http.HandlerFunc
+}
@@ -0,0 +1,62 @@
+package route
+
+import (
+	"context"
+	"net/http"
+)
+
+type stateKey struct{}
+
+//state is the routing state of a single request. It is shared by every Router
+//that takes part in routing that request, so values recorded by one Router
+//(a Path capturing a segment, say) are visible to those further down the tree
+//and to the Handler the route terminates in.
+type state struct {
+	captures []Capture
+	//the VerbHandlers of the nearest RouteHandler
+	options, notAllowed VerbHandler
+	//the NotAcceptable handler of the nearest RouteHandler
+	notAcceptable http.Handler
+	//the URL path before the first Mount stripped it
+	mounted      bool
+	originalPath string
+	//the Tracer of the RouteHandler that enabled tracing, and the trace
+	tracer *Tracer
+	trace  Trace
+	//the PathPolicy of the nearest RouteHandler, and the canonical path
+	//if a FoldPath matched a name not spelled as its key
+	paths         PathPolicy
+	trailingSlash bool
+	respelled     bool
+	canonical     string
+	//whether a Mount hands part of the path on to its Handler
+	handedOn bool
+	//the value the Handler panicked with
+	recovered interface{}
+}
+
+//Function withState returns rq, or a shallow copy of rq with a fresh routing
+//state if it does not already carry one.
+func withState(rq *http.Request) *http.Request {
+	if _, ok := rq.Context().Value(stateKey{}).(*state); ok {
+		return rq
+	}
+	return rq.WithContext(context.WithValue(rq.Context(), stateKey{}, new(state)))
+}
+
+//Function stateOf returns the routing state of rq. Routers called outside
+//of a RouteHandler have no state to hand, so in that case one is attached to
+//rq in place.
+func stateOf(rq *http.Request) *state {
+	if s, ok := rq.Context().Value(stateKey{}).(*state); ok {
+		return s
+	}
+	*rq = *withState(rq)
+	return rq.Context().Value(stateKey{}).(*state)
+}
+
+//peekState returns the routing state of rq, or nil if there is none.
+func peekState(rq *http.Request) *state {
+	s, _ := rq.Context().Value(stateKey{}).(*state)
+	return s
+}
@@ -2,7 +2,6 @@ package route
 
 import (
 	"fmt"
-	"log"
 	"net/http"
 	"path"
 	"reflect"
@@ -25,11 +24,9 @@ var _ Router = &Subdomain{}
 
 //function removeLevel removes the highest level domain from a domain name
 func popLevel(domain string) (newDomain, oldLevel string) {
-	var lastdot uint
-	for i, v := range domain {
-		if v == '.' {
-			lastdot = uint(i)
-		}
+	lastdot := strings.LastIndex(domain, ".")
+	if lastdot == -1 {
+		return "", domain
 	}
 	return domain[:lastdot], domain[lastdot+1:]
 }
@@ -40,6 +37,21 @@ func popLevel(domain string) (newDomain, oldLevel string) {
 	below them.
 
 	The empty subdomain ("") is used when the route terminates here.
+
+	A wildcard label ("&tenant") matches any one label that nothing
+	else in the Subdomain does, and records it against the request
+	under tenant, to be retrieved with Param. Wildcards can be nested:
+
+		Subdomain{
+			"example.com": Subdomain{
+				"&tenant": Subdomain{
+					"&region": r,
+				},
+			},
+		}
+
+	routes eu.acme.example.com to r, with tenant "acme" and region "eu".
+	A Subdomain should have at most one wildcard.
 */
 type Subdomain map[string]Router
 
@@ -53,15 +65,6 @@ func (s Subdomain) here() Router {
 	return s[termHere]
 }
 
-func removeSubdomain(subd, path string) (s string) {
-	s = strings.TrimSuffix(path, subd)
-	if len(s) > 0 {
-		s = strings.Trim(s, ".")
-	}
-
-	return
-}
-
 // isSubdomin returns the value of the assertion
 // `if r implements SubdomainRouter`. If the assertion is true,
 // sd is set to the DomainRouter of r.
@@ -89,17 +92,19 @@ func (s Subdomain) RouteHTTP(rq *http.Request) Router {
 	//fix odd domains (x.com..x)
 	domain = strings.Replace(path.Clean(strings.Replace(domain, ".", "/", -1)), "/", ".", -1)
 
+	//host names are not case sensitive, and the keys of a Subdomain
+	//should be lower case.
+	domain = strings.ToLower(domain)
+
+	st := peekState(rq)
 	for {
-		if debug {
-			log.Printf("Route is now %+q\n", domain)
-		}
+		tried := domain
 		currentRouter, domain = currentSubdomain.Subdomain(domain)
+		currentRouter, _ = unwrap(rq, currentRouter)
+		st.step(currentSubdomain, tried, domain, currentRouter)
 
 		var ok bool
 		if currentSubdomain, ok = currentRouter.(DomainRouter); !ok {
-			if debug {
-				log.Println("Subdomain routing terminated at:", reflect.TypeOf(currentRouter), reflect.TypeOf(currentSubdomain))
-			}
 			break
 		}
 	}
@@ -115,55 +120,65 @@ func (s Subdomain) String() (o string) {
 	return o[:len(o)-1] + "]"
 }
 
-func debRoute(ty, message string, v interface{}) {
-	var targetS string
-	if k, ok := v.(Subdomain); ok {
-		targetS = k.String()
-	} else {
-		targetS = reflect.ValueOf(v).String()
+//Function labelSuffix reports whether suffix is a suffix of domain made of
+//whole labels, returning what is left of domain without it.
+func labelSuffix(domain, suffix string) (remaining string, ok bool) {
+	switch {
+	case domain == suffix:
+		return "", true
+	case len(domain) > len(suffix) &&
+		strings.HasSuffix(domain, suffix) &&
+		domain[len(domain)-len(suffix)-1] == '.':
+		return domain[:len(domain)-len(suffix)-1], true
 	}
-
-	fmt.Printf("%+q, %+q, %+q\n", ty, message, targetS)
+	return "", false
 }
 
 //Function Subdomain is provided by all types implementing the
 //SubdomainRouter interface.
+//
+//The domain is routed by the longest key that is a suffix of it, made of
+//whole labels: "api.example.com" is routed by "api.example.com" over
+//"example.com", and "badexample.com" is not routed by "example.com". If no
+//key is, the highest level label is swallowed by the wildcard, if present.
 func (s Subdomain) Subdomain(subpath string) (Router, string) {
-
-	//Check if we have bound a handler for the entire remaining route.
-	if sD, ok := s[subpath]; ok {
-		if debug {
-			debRoute(subpath, "represents whole path in", sD)
-		}
-		//Nothing left.
-		return sD, ""
+	if subpath == termHere {
+		return s.here(), ""
 	}
 
-	//Check if the next node is present
-	var cSubpath, cLevel string
-	cSubpath, cLevel = popLevel(subpath)
-	//If the subpath is "empty", then we return this Subdomain's PathRouter
-	if rT, ok := s[cLevel]; ok {
-		if debug {
-			debRoute(cLevel, "routes to ", rT)
+	var (
+		longest   string
+		remaining string
+		found     bool
+	)
+	for k := range s {
+		if isSpecial(k) || found && len(k) <= len(longest) {
+			continue
+		}
+		if r, ok := labelSuffix(subpath, k); ok {
+			longest, remaining, found = k, r, true
 		}
-		return rT, cSubpath
+	}
+	if found {
+		return s[longest], remaining
 	}
 
-	//If the requested domain is the suffix of the current domain
-	//strip off that component as per its map.
-	for subDomain, router := range s {
-		if subDomain != termHere && strings.HasSuffix(subpath, subDomain) {
-			if debug {
-				debRoute(subDomain, "is a suffix of", subpath)
-			}
-			return router, removeSubdomain(subDomain, subpath)
+	cSubpath, cLevel := popLevel(subpath)
+
+	//Otherwise, the next level can be swallowed by a wildcard.
+	if name, r := ampersand(s); r != nil {
+		if name == "" {
+			return r, cSubpath
 		}
+		return captured{
+			Capture: Capture{
+				Name:  name,
+				Value: cLevel,
+			},
+			Router: r,
+		}, cSubpath
 	}
 
-	if debug {
-		debRoute(subpath, "-- none matched, 404", nil)
-	}
 	return nil, subpath
 }
 
@@ -177,3 +192,110 @@ func (s Subdomain) Domain(name string, r Router) Subdomain {
 	}
 	return s
 }
+
+//A DomainError is a problem with a key of a Subdomain, found by CheckDomains.
+type DomainError struct {
+	//Host is the pattern of the host the Subdomain is reached by, as in Route.
+	Host    string
+	Key     string
+	Problem string
+}
+
+func (d DomainError) Error() string {
+	return fmt.Sprintf("route: Subdomain key %+q under %+q %s", d.Key, d.Host, d.Problem)
+}
+
+type DomainErrors []DomainError
+
+func (d DomainErrors) Error() string {
+	s := make([]string, len(d))
+	for i, e := range d {
+		s[i] = e.Error()
+	}
+	return strings.Join(s, "\n")
+}
+
+//Function CheckDomains checks the keys of every Subdomain in the tree r,
+//returning DomainErrors for those that cannot be matched, or are ambiguous:
+//keys that are not lower case, have empty labels or ports, a Subdomain with
+//more than one wildcard, and keys that shadow a route in a shorter key's
+//Subdomain, such as "api.example.com" alongside "example.com" leading to
+//a Subdomain with "api".
+//
+//Routing is deterministic regardless; CheckDomains should be called when
+//a tree is built, to find the routes that cannot be reached.
+func CheckDomains(r Router) error {
+	var errs DomainErrors
+	descend(r, func(r Router, t trail) bool {
+		s, ok := r.(Subdomain)
+		if !ok {
+			return true
+		}
+
+		host := hostPattern(t.domain)
+		problem := func(key, format string, a ...interface{}) {
+			errs = append(errs, DomainError{
+				Host:    host,
+				Key:     key,
+				Problem: fmt.Sprintf(format, a...),
+			})
+		}
+
+		var wildcards int
+		for _, k := range sortedKeys(s) {
+			switch {
+			case k == termHere:
+				continue
+			case isAmpersand(k):
+				if wildcards++; wildcards == 2 {
+					problem(k, "is one of several wildcards")
+				}
+				continue
+			case strings.ToLower(k) != k:
+				problem(k, "is not lower case")
+			case strings.Contains(k, ":"):
+				problem(k, "has a port")
+			case k[0] == '.' || k[len(k)-1] == '.' || strings.Contains(k, ".."):
+				problem(k, "has an empty label")
+			}
+
+			for _, short := range sortedKeys(s) {
+				if isSpecial(short) || short == k {
+					continue
+				}
+				prefix, ok := labelSuffix(k, short)
+				if !ok {
+					continue
+				}
+				if inner, ok := s[short].(Subdomain); ok && inner.matches(prefix) {
+					problem(k, "shadows %+q in the Subdomain of %+q", prefix, short)
+				}
+			}
+		}
+		return true
+	})
+
+	if errs != nil {
+		return errs
+	}
+	return nil
+}
+
+//Function Check is CheckDomains for this Subdomain.
+func (s Subdomain) Check() error {
+	return CheckDomains(s)
+}
+
+//Function matches reports whether a key of s, other than a wildcard,
+//matches domain.
+func (s Subdomain) matches(domain string) bool {
+	for k := range s {
+		if isSpecial(k) {
+			continue
+		}
+		if _, ok := labelSuffix(domain, k); ok {
+			return true
+		}
+	}
+	return false
+}
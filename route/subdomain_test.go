@@ -0,0 +1,92 @@
+package route
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestSubdomainWildcard(t *testing.T) {
+	h := RouteHandler{
+		Router: Subdomain{
+			"app.example.com": Subdomain{
+				"www": paramsHandler("tenant"),
+				"&tenant": Subdomain{
+					"":        paramsHandler("tenant"),
+					"&region": Path{"&page": paramsHandler("tenant", "region", "page")},
+				},
+			},
+		},
+		NotFound: NotFound,
+	}
+
+	for url, expected := range map[string]string{
+		"http://www.app.example.com":             "tenant=;",
+		"http://acme.app.example.com:8080/":      "tenant=acme;",
+		"http://eu.acme.app.example.com/billing": "tenant=acme;region=eu;page=billing;",
+	} {
+		rw := serve(h, "GET", url)
+		if rw.Code != http.StatusOK || rw.Body.String() != expected {
+			t.Errorf("%s: expected %q, got %d %q", url, expected, rw.Code, rw.Body.String())
+		}
+	}
+
+	if rw := serve(h, "GET", "http://app.example.com/"); rw.Code != http.StatusNotFound {
+		t.Errorf("expected 404 without a tenant, got %d", rw.Code)
+	}
+
+	u, err := URL(Subdomain{
+		"example.com": Subdomain{"&tenant": Name("home", paramsHandler())},
+	}, "home", "tenant", "acme")
+	if err != nil || u.String() != "//acme.example.com/" {
+		t.Errorf("expected //acme.example.com/, got %v %v", u, err)
+	}
+}
+
+func TestSubdomainLongestSuffix(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		s := Subdomain{
+			"com":             paramsHandler("com"),
+			"example.com":     paramsHandler("example"),
+			"api.example.com": paramsHandler("api"),
+		}
+		for host, expected := range map[string]string{
+			"api.example.com":    "api=;",
+			"v1.api.example.com": "api=;",
+			"www.example.com":    "example=;",
+			"badexample.com":     "com=;",
+			"EXAMPLE.com":        "example=;",
+		} {
+			rw := serve(RouteHandler{Router: s}, "GET", "http://"+host+"/")
+			if rw.Body.String() != expected {
+				t.Fatalf("%s: expected %q, got %q", host, expected, rw.Body.String())
+			}
+		}
+	}
+}
+
+func TestCheckDomains(t *testing.T) {
+	good := Subdomain{
+		"example.com": Subdomain{
+			"www":     paramsHandler(),
+			"&tenant": paramsHandler(),
+		},
+		"api.example.net": paramsHandler(),
+	}
+	if err := good.Check(); err != nil {
+		t.Errorf("expected no error, got %s", err)
+	}
+
+	bad := Path{
+		"a": Subdomain{
+			"Example.com":     paramsHandler(),
+			"example.com":     Subdomain{"api": paramsHandler()},
+			"api.example.com": paramsHandler(),
+			"&a":              paramsHandler(),
+			"&b":              paramsHandler(),
+		},
+	}
+	errs, _ := CheckDomains(bad).(DomainErrors)
+	if len(errs) != 3 {
+		t.Fatalf("expected 3 errors, got %v", errs)
+	}
+}
@@ -0,0 +1,139 @@
+package route
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//A Step is one step taken routing a request: a Router trying a key
+//(a path segment, a domain, a method) and the Router that led to.
+type Step struct {
+	//the type of the Router, as "route.Path"
+	Router string
+	Key    string
+	//what is left of the path or domain being routed
+	Remaining string
+	//the type of the Router the step led to, or "nil" if none did
+	Result string
+}
+
+func (s Step) String() string {
+	o := fmt.Sprintf("%s %+q -> %s", s.Router, s.Key, s.Result)
+	if s.Remaining != "" {
+		o += fmt.Sprintf(" (%+q left)", s.Remaining)
+	}
+	return o
+}
+
+//A Trace is the Steps taken routing a request, in order.
+type Trace []Step
+
+//Function String returns the Steps of the Trace on one line, so that it
+//can be sent as a header.
+func (t Trace) String() string {
+	steps := make([]string, len(t))
+	for i, s := range t {
+		steps[i] = s.String()
+	}
+	return strings.Join(steps, "; ")
+}
+
+//A Tracer configures the tracing of the requests routed by a RouteHandler.
+//Requests for which Enabled returns true have the steps taken by the Paths,
+//Subdomains, Verbs and other Routers of this package recorded as they are
+//routed.
+//
+//The Trace is sent as the response header Header, if set, before the
+//Handler the route ends at is served, and passed to Log, if set, once it
+//has been.
+type Tracer struct {
+	Enabled func(rq *http.Request) bool
+	Header  string
+	Log     func(rq *http.Request, t Trace)
+}
+
+//LogTrace is a convenience function for the Log of a Tracer, which logs the
+//Trace with the standard logger.
+func LogTrace(rq *http.Request, t Trace) {
+	log.Printf("[?] Route of %s %+q: %s", rq.Method, rq.Host+rq.URL.Path, t)
+}
+
+//Function TraceOf returns the Trace of rq so far, or nil if it is
+//not traced.
+func TraceOf(rq *http.Request) Trace {
+	if s := peekState(rq); s != nil && s.tracer != nil {
+		return append(Trace(nil), s.trace...)
+	}
+	return nil
+}
+
+//Function step records a Step in the trace of the request, if it is traced.
+//s may be nil.
+func (s *state) step(router interface{}, key, remaining string, result Router) {
+	if s == nil || s.tracer == nil {
+		return
+	}
+	rs := "nil"
+	if result != nil {
+		rs = fmt.Sprintf("%T", result)
+	}
+	s.trace = append(s.trace, Step{
+		Router:    fmt.Sprintf("%T", router),
+		Key:       key,
+		Remaining: remaining,
+		Result:    rs,
+	})
+}
+
+//Function sendTrace sets the trace header, if there is a trace to send.
+func (s *state) sendTrace(rw http.ResponseWriter) {
+	if s.tracer != nil && s.tracer.Header != "" {
+		rw.Header().Set(s.tracer.Header, s.trace.String())
+	}
+}
+
+//Function logTrace logs the trace, if there is a trace to log.
+func (s *state) logTrace(rq *http.Request) {
+	if s.tracer != nil && s.tracer.Log != nil {
+		s.tracer.Log(rq, s.trace)
+	}
+}
+
+//Function TraceToken returns a value for the header checked by SignedHeader,
+//signed with key, that enables tracing until expires.
+func TraceToken(key []byte, expires time.Time) string {
+	t := strconv.FormatInt(expires.Unix(), 10)
+	return t + ":" + hex.EncodeToString(sign(key, t))
+}
+
+func sign(key []byte, value string) []byte {
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte(value))
+	return m.Sum(nil)
+}
+
+//Function SignedHeader returns a function for the Enabled of a Tracer that
+//enables tracing for requests whose header name holds a token made by
+//TraceToken with key, that has not expired.
+func SignedHeader(name string, key []byte) func(*http.Request) bool {
+	return func(rq *http.Request) bool {
+		token := rq.Header.Get(name)
+		i := strings.IndexByte(token, ':')
+		if i < 0 {
+			return false
+		}
+		expires, err := strconv.ParseInt(token[:i], 10, 64)
+		if err != nil || time.Now().Unix() > expires {
+			return false
+		}
+		mac, err := hex.DecodeString(token[i+1:])
+		return err == nil && hmac.Equal(mac, sign(key, token[:i]))
+	}
+}
@@ -0,0 +1,68 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTrace(t *testing.T) {
+	key := []byte("secret")
+	var logged Trace
+	h := RouteHandler{
+		Router: Subdomain{
+			"example.com": Path{
+				"users": Path{
+					"&id": GetOnly(paramsHandler("id")),
+				},
+			},
+		},
+		NotFound: NotFound,
+		Tracer: &Tracer{
+			Enabled: SignedHeader("X-Trace", key),
+			Header:  "X-Route-Trace",
+			Log: func(rq *http.Request, t Trace) {
+				logged = t
+			},
+		},
+	}
+
+	for _, c := range []struct {
+		token  string
+		traced bool
+	}{
+		{"", false},
+		{TraceToken(key, time.Now().Add(time.Minute)), true},
+		{TraceToken(key, time.Now().Add(-time.Minute)), false},
+		{TraceToken([]byte("guess"), time.Now().Add(time.Minute)), false},
+	} {
+		logged = nil
+		rw := httptest.NewRecorder()
+		rq, _ := http.NewRequest("GET", "http://example.com/users/bob", nil)
+		rq.Header.Set("X-Trace", c.token)
+		h.ServeHTTP(rw, rq)
+
+		header := rw.Header().Get("X-Route-Trace")
+		if !c.traced {
+			if header != "" || logged != nil {
+				t.Errorf("%+q: traced when not enabled", c.token)
+			}
+			continue
+		}
+
+		expected := Trace{
+			{"route.RouteHandler", "example.com/users/bob", "", "route.Subdomain"},
+			{"route.Subdomain", "example.com", "", "route.Path"},
+			{"route.Path", "users", "bob", "route.Path"},
+			{"route.Path", "bob", "", "route.Verb"},
+			{"route.Verb", "GET", "", "route.Handler"},
+		}
+		if header != expected.String() {
+			t.Errorf("expected the trace header\n%s\ngot\n%s", expected, header)
+		}
+		if logged.String() != expected.String() {
+			t.Errorf("expected the trace logged\n%s\ngot\n%s", expected, logged)
+		}
+	}
+}
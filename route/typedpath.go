@@ -0,0 +1,131 @@
+package route
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	_ PathingRouter = TypedPath{}
+	_ Router        = TypedPath{}
+)
+
+//A SegmentMatcher decides whether a path segment is acceptable, returning
+//the value it parses the segment to.
+type SegmentMatcher interface {
+	MatchSegment(segment string) (parsed interface{}, ok bool)
+}
+
+type SegmentMatcherFunc func(segment string) (parsed interface{}, ok bool)
+
+func (s SegmentMatcherFunc) MatchSegment(segment string) (interface{}, bool) {
+	return s(segment)
+}
+
+//Int matches base 10 integers, parsing them to an int64.
+var Int SegmentMatcher = SegmentMatcherFunc(func(s string) (interface{}, bool) {
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	return i, true
+})
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+//UUID matches UUIDs in their hyphenated form, parsing them to a lower case string.
+var UUID SegmentMatcher = SegmentMatcherFunc(func(s string) (interface{}, bool) {
+	if !uuidPattern.MatchString(s) {
+		return nil, false
+	}
+	return strings.ToLower(s), true
+})
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+//Slug matches lower case alphanumeric words joined by single hyphens
+//("my-first-post"), parsing them to a string.
+var Slug SegmentMatcher = SegmentMatcherFunc(func(s string) (interface{}, bool) {
+	if !slugPattern.MatchString(s) {
+		return nil, false
+	}
+	return s, true
+})
+
+//Function Regexp returns a SegmentMatcher that accepts segments which r
+//matches in their entirety. The parsed value is the []string of submatches
+//returned by FindStringSubmatch.
+func Regexp(r *regexp.Regexp) SegmentMatcher {
+	anchored := regexp.MustCompile(`^(?:` + r.String() + `)$`)
+	return SegmentMatcherFunc(func(s string) (interface{}, bool) {
+		m := anchored.FindStringSubmatch(s)
+		if m == nil {
+			return nil, false
+		}
+		return m, true
+	})
+}
+
+//A Matcher routes path segments accepted by Match to Router, capturing them
+//under Name. A Matcher with no Name captures nothing.
+type Matcher struct {
+	Name   string
+	Match  SegmentMatcher
+	Router Router
+}
+
+//Type TypedPath is a Path that can also match segments by type or pattern.
+//A segment is routed by the exact keys of the Path first, then by each
+//of the Matchers in order, and finally by the Path's ampersand, if present.
+type TypedPath struct {
+	Path
+	Matchers []Matcher
+}
+
+//Function Match adds a Matcher after those already present.
+func (t TypedPath) Match(name string, m SegmentMatcher, r Router) TypedPath {
+	t.Path = t.Path.self()
+	t.Matchers = append(t.Matchers, Matcher{
+		Name:   name,
+		Match:  m,
+		Router: r,
+	})
+	return t
+}
+
+//Function Child is provided by all types implementing the PathingRouter
+//interface.
+func (t TypedPath) Child(subpath string) (Router, string) {
+	if s := strings.TrimLeft(subpath, "/"); s != "" {
+		remaining, popped := swallowOne(s)
+		if r, ok := t.Path[popped]; ok && !isSpecial(popped) {
+			return r, remaining
+		}
+
+		for _, m := range t.Matchers {
+			v, ok := m.Match.MatchSegment(popped)
+			if !ok {
+				continue
+			}
+			if m.Name == "" {
+				return m.Router, remaining
+			}
+			return captured{
+				Capture: Capture{
+					Name:   m.Name,
+					Value:  popped,
+					Parsed: v,
+				},
+				Router: m.Router,
+			}, remaining
+		}
+	}
+
+	return t.Path.Child(subpath)
+}
+
+func (t TypedPath) RouteHTTP(rq *http.Request) Router {
+	return PathRouteHTTP(t, rq)
+}
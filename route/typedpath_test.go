@@ -0,0 +1,39 @@
+package route
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestTypedPath(t *testing.T) {
+	h := RouteHandler{
+		Router: Path{
+			"users": TypedPath{
+				Path: Path{
+					"me": paramsHandler("id"),
+					"&":  paramsHandler("id"),
+				},
+			}.Match(
+				"id", Int, HandleFunc(func(rw http.ResponseWriter, rq *http.Request) {
+					fmt.Fprintf(rw, "int %d", ParsedParam(rq, "id").(int64))
+				}),
+			).Match(
+				"id", UUID, paramsHandler("id"),
+			),
+		},
+		NotFound: NotFound,
+	}
+
+	for url, expected := range map[string]string{
+		"http://example.com/users/me":                                   "id=;",
+		"http://example.com/users/123":                                  "int 123",
+		"http://example.com/users/123e4567-e89b-12d3-a456-426614174000": "id=123e4567-e89b-12d3-a456-426614174000;",
+		"http://example.com/users/bob":                                  "id=;",
+	} {
+		rw := serve(h, "GET", url)
+		if rw.Code != http.StatusOK || rw.Body.String() != expected {
+			t.Errorf("%s: expected %q, got %d %q", url, expected, rw.Code, rw.Body.String())
+		}
+	}
+}
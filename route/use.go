@@ -0,0 +1,236 @@
+package route
+
+import (
+	"github.com/TShadwell/fweight"
+	"net/http"
+	"reflect"
+)
+
+var (
+	_ PathingRouter = wrappedPath{}
+	_ DomainRouter  = wrappedDomain{}
+	_ PathingRouter = wrappedPathDomain{}
+	_ DomainRouter  = wrappedPathDomain{}
+)
+
+//Function Use returns a copy of the tree r with every Handler it ends in
+//wrapped by mw, so that middleware such as authentication can apply to
+//a part of a tree alone:
+//
+//	Path{
+//		"admin": route.Use(admin, auth),
+//		"api":   route.Use(api, compression.Middleware),
+//	}
+//
+//As in a Pipeline, each Middleware wraps the Handler produced by those
+//before it. A subtree passed to Use within r is wrapped again, so
+//middleware nearer the root is run first.
+//
+//The Handlers of the Routers of this package are wrapped once, when Use
+//is called, and the copy routes as r did; Paths stay PathingRouters, so
+//Use can be placed anywhere in a trie. The Handlers other Routers return,
+//like a RouterFunc, are only known once a request is made, so they are
+//wrapped on each request. Later changes to r are not seen by the copy.
+func Use(r Router, mw ...fweight.Middleware) Router {
+	if len(mw) == 0 {
+		return r
+	}
+	return rewriter{
+		wrap: func(h http.Handler) http.Handler {
+			for _, m := range mw {
+				h = m.Middleware(h)
+			}
+			return h
+		},
+		seen: make(map[mapKey]interface{}),
+	}.rewrite(r)
+}
+
+//A rewriter copies trees of the Routers of this package, applying wrap,
+//if set, to each of the http.Handlers they end in.
+type rewriter struct {
+	wrap func(http.Handler) http.Handler
+	//copies by the type and address of the map copied, so that shared
+	//and recursive trees copy to shared and recursive trees.
+	seen map[mapKey]interface{}
+}
+
+type mapKey struct {
+	reflect.Type
+	addr uintptr
+}
+
+//Function copyMap copies m into the map to, which is recorded as the copy
+//of m before its children are copied. copied is to, as the type of m.
+func (w rewriter) copyMap(m, to map[string]Router, copied interface{}) {
+	w.seen[mapKey{reflect.TypeOf(copied), reflect.ValueOf(m).Pointer()}] = copied
+	for k, v := range m {
+		to[k] = w.rewrite(v)
+	}
+}
+
+func (w rewriter) rewrite(r Router) Router {
+	if r == nil {
+		return nil
+	}
+	if m := reflect.ValueOf(r); m.Kind() == reflect.Map && !m.IsNil() {
+		if c, ok := w.seen[mapKey{m.Type(), m.Pointer()}]; ok {
+			return c.(Router)
+		}
+	}
+
+	if w.wrap == nil {
+		switch r.(type) {
+		case Handler, Mount:
+			return r
+		}
+	}
+
+	switch t := r.(type) {
+	case Handler:
+		return Handle(w.wrap(t.Handler))
+	case Mount:
+		return Mount{w.wrap(t.Handler)}
+	case Path:
+		c := make(Path, len(t))
+		w.copyMap(t, c, c)
+		return c
+	case NoExtPath:
+		c := make(NoExtPath, len(t))
+		w.copyMap(t, c, c)
+		return c
+	case FoldPath:
+		c := make(FoldPath, len(t))
+		w.copyMap(t, c, c)
+		return c
+	case FoldNoExtPath:
+		c := make(FoldNoExtPath, len(t))
+		w.copyMap(t, c, c)
+		return c
+	case Subdomain:
+		c := make(Subdomain, len(t))
+		w.copyMap(t, c, c)
+		return c
+	case Verb:
+		c := make(Verb, len(t))
+		w.copyMap(t, c, c)
+		return c
+	case Media:
+		c := make(Media, len(t))
+		w.copyMap(t, c, c)
+		return c
+	case TypedPath:
+		c := TypedPath{
+			Path:     w.rewrite(t.Path).(Path),
+			Matchers: make([]Matcher, len(t.Matchers)),
+		}
+		for i, m := range t.Matchers {
+			m.Router = w.rewrite(m.Router)
+			c.Matchers[i] = m
+		}
+		return c
+	case Methods:
+		t.Verb = w.rewrite(t.Verb).(Verb)
+		return t
+	case Named:
+		t.Router = w.rewrite(t.Router)
+		return t
+	case RouteHandler:
+		t.Router = w.rewrite(t.Router)
+		return t
+	case Header:
+		t.Values, t.Default = w.values(t.Values), w.rewrite(t.Default)
+		return t
+	case Query:
+		t.Values, t.Default = w.values(t.Values), w.rewrite(t.Default)
+		return t
+	case Cookie:
+		t.Values, t.Default = w.values(t.Values), w.rewrite(t.Default)
+		return t
+	}
+	if w.wrap == nil {
+		return r
+	}
+	return rewrap(r, w.wrap)
+}
+
+func (w rewriter) values(m map[string]Router) map[string]Router {
+	if m == nil {
+		return nil
+	}
+	if c, ok := w.seen[mapKey{reflect.TypeOf(m), reflect.ValueOf(m).Pointer()}]; ok {
+		return c.(map[string]Router)
+	}
+	c := make(map[string]Router, len(m))
+	w.copyMap(m, c, c)
+	return c
+}
+
+//wrappedPath is a wrapped PathingRouter, which takes part in the descent of
+//the path trie as its Router would.
+type wrappedPath struct {
+	wrapped
+}
+
+//Function Child is provided by all types implementing the PathingRouter
+//interface.
+func (w wrappedPath) Child(subpath string) (Router, string) {
+	r, remaining := w.Router.(PathingRouter).Child(subpath)
+	return rewrapChild(r, w.wrap), remaining
+}
+
+//wrappedDomain is a wrapped DomainRouter, which takes part in the descent
+//of the domain trie as its Router would.
+type wrappedDomain struct {
+	wrapped
+}
+
+//Function Subdomain is provided by all types implementing the DomainRouter
+//interface.
+func (w wrappedDomain) Subdomain(subpath string) (Router, string) {
+	r, remaining := w.Router.(DomainRouter).Subdomain(subpath)
+	return rewrapChild(r, w.wrap), remaining
+}
+
+//wrappedPathDomain is a wrapped Router that is both a PathingRouter and a
+//DomainRouter, like an Atomic.
+type wrappedPathDomain struct {
+	wrappedPath
+}
+
+//Function Subdomain is provided by all types implementing the DomainRouter
+//interface.
+func (w wrappedPathDomain) Subdomain(subpath string) (Router, string) {
+	return wrappedDomain{w.wrapped}.Subdomain(subpath)
+}
+
+//Function rewrapChild wraps the child r of a wrapped PathingRouter or
+//DomainRouter, keeping what it captured or respelled.
+func rewrapChild(r Router, wrap func(http.Handler) http.Handler) Router {
+	switch c := r.(type) {
+	case captured:
+		c.Router = rewrap(c.Router, wrap)
+		return c
+	case respelled:
+		c.Router = rewrap(c.Router, wrap)
+		return c
+	}
+	return rewrap(r, wrap)
+}
+
+func rewrap(r Router, wrap func(http.Handler) http.Handler) Router {
+	if r == nil {
+		return nil
+	}
+	_, pathing := r.(PathingRouter)
+	_, domain := r.(DomainRouter)
+	switch {
+	case pathing && domain:
+		return wrappedPathDomain{wrappedPath{wrapped{r, wrap}}}
+	case pathing:
+		return wrappedPath{wrapped{r, wrap}}
+	case domain:
+		return wrappedDomain{wrapped{r, wrap}}
+	}
+	return wrapped{r, wrap}
+}
@@ -0,0 +1,93 @@
+package route
+
+import (
+	"fmt"
+	"github.com/TShadwell/fweight"
+	"net/http"
+	"testing"
+)
+
+func mark(name string) fweight.Middleware {
+	return fweight.MiddlewareFunc(func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, rq *http.Request) {
+			fmt.Fprintf(rw, "%s;", name)
+			h.ServeHTTP(rw, rq)
+		})
+	})
+}
+
+func TestUse(t *testing.T) {
+	calls := 0
+	counted := fweight.MiddlewareFunc(func(h http.Handler) http.Handler {
+		calls++
+		return h
+	})
+
+	user := Path{
+		"&id": paramsHandler("id"),
+	}
+	admin := Path{
+		"":      paramsHandler(),
+		"users": Use(user, mark("users"), counted),
+		"old": RouterFunc(func(rq *http.Request) Router {
+			return paramsHandler()
+		}),
+		"static": Compile(Path{"&file": paramsHandler("file")}),
+	}
+	tree := Path{
+		"":      paramsHandler(),
+		"admin": Use(admin, mark("auth")),
+	}
+	h := RouteHandler{Router: Use(tree, mark("log")), NotFound: NotFound}
+
+	for url, expected := range map[string]string{
+		"http://example.com/":                "log;",
+		"http://example.com/admin":           "log;auth;",
+		"http://example.com/admin/users/bob": "log;auth;users;id=bob;",
+		"http://example.com/admin/old":       "log;auth;",
+		"http://example.com/admin/static/a":  "log;auth;file=a;",
+	} {
+		if got := serve(h, "GET", url).Body.String(); got != expected {
+			t.Errorf("%s: expected %q, got %q", url, expected, got)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("expected the middleware to be applied once, got %d", calls)
+	}
+	if _, ok := user["&id"].(Handler); !ok || len(user) != 1 {
+		t.Error("the tree passed to Use was changed")
+	}
+
+	loop := Path{"": paramsHandler()}
+	loop["again"] = loop
+	looped := Use(loop, mark("loop")).(Path)
+	if looped["again"].(Path)["again"].(Path)["again"] == nil {
+		t.Error("a tree containing itself was not copied to one")
+	}
+}
+
+func TestUseSubdomain(t *testing.T) {
+	h := RouteHandler{
+		Router: Subdomain{
+			"example.com": Use(NewAtomic(Subdomain{
+				"api":     paramsHandler(),
+				"&tenant": paramsHandler("tenant"),
+			}), mark("api")),
+			"example.org": Use(Subdomain{
+				"www": paramsHandler(),
+			}, mark("www")),
+		},
+		NotFound: NotFound,
+	}
+
+	for url, expected := range map[string]string{
+		"http://api.example.com/":  "api;",
+		"http://acme.example.com/": "api;tenant=acme;",
+		"http://www.example.org/":  "www;",
+	} {
+		if got := serve(h, "GET", url).Body.String(); got != expected {
+			t.Errorf("%s: expected %q, got %q", url, expected, got)
+		}
+	}
+}
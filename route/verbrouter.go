@@ -1,7 +1,10 @@
 package route
 
 import (
+	"fmt"
 	"net/http"
+	"sort"
+	"strings"
 )
 
 func GetOnly(r Router) Verb {
@@ -10,39 +13,142 @@ func GetOnly(r Router) Verb {
 	}
 }
 
-var _ Router = make(Verb)
+var (
+	_ Router = make(Verb)
+	_ Router = Methods{}
+)
 
-var OptionsHandler func(i interface{}, rw http.ResponseWriter, rq *http.Request)
-var MethodNotAllowed func(i interface{}, rw http.ResponseWriter, rq *http.Request)
+//A VerbHandler answers OPTIONS requests, or those with a method that is not
+//allowed. i is the []string of methods that are, as returned by Verbs.
+//
+//The signature is that of object.Handler's ServeObject.
+type VerbHandler func(i interface{}, rw http.ResponseWriter, rq *http.Request)
+
+//defaultMethodNotAllowed is the VerbHandler used for 405 Method Not Allowed
+//if none is configured.
+func defaultMethodNotAllowed(i interface{}, rw http.ResponseWriter, rq *http.Request) {
+	rw.Header().Set("Content-Type", "text/plain;charset=utf8")
+	fmt.Fprintf(rw, "Method %s is not allowed, only %s.", rq.Method, strings.Join(i.([]string), ", "))
+}
 
+//Function Verbs returns the methods this Verb answers, sorted: those with
+//a non-nil Router. HEAD is included if GET is present, and OPTIONS always
+//is.
 func (v Verb) Verbs() (ops []string) {
-	ops = make([]string, len(v))
-	if OptionsHandler != nil {
-		ops = append(ops, "OPTIONS")
+	ops = make([]string, 0, len(v)+2)
+	for k, r := range v {
+		if r != nil {
+			ops = append(ops, k)
+		}
 	}
-	var i uint
-	for k := range v {
-		ops[i] = k
-		i++
+	if v["HEAD"] == nil && v["GET"] != nil {
+		ops = append(ops, "HEAD")
 	}
+	if v["OPTIONS"] == nil {
+		ops = append(ops, "OPTIONS")
+	}
+	sort.Strings(ops)
 	return
 }
 
+//RouteHTTP routes the request by its method. A HEAD request is
+//routed as a GET request if HEAD is not present but GET is; net/http
+//throws away the body of the response.
+//
+//OPTIONS requests are answered with the Allow header, then
+//the Options VerbHandler, unless OPTIONS is present. Requests with other
+//methods are answered with 405 Method Not Allowed, the Allow header,
+//and the MethodNotAllowed VerbHandler.
+//
+//The VerbHandlers are those of the nearest RouteHandler the request
+//was routed through. If there are none, OPTIONS requests are answered
+//with 204 No Content, and a short plain text message is written for
+//405 Method Not Allowed. See Methods to configure them for one Verb.
 func (v Verb) RouteHTTP(rq *http.Request) Router {
-	if OptionsHandler != nil && rq.Method == "OPTIONS" {
-		return Handle(http.HandlerFunc(func(rw http.ResponseWriter, rq *http.Request) {
-			OptionsHandler(v.Verbs(), rw, rq)
-		}))
+	return v.route(rq, nil, nil)
+}
+
+func (v Verb) route(rq *http.Request, options, notAllowed VerbHandler) Router {
+	r := v.pick(rq, options, notAllowed)
+	peekState(rq).step(v, rq.Method, "", r)
+	return r
+}
+
+func (v Verb) pick(rq *http.Request, options, notAllowed VerbHandler) Router {
+	if s := peekState(rq); s != nil {
+		if options == nil {
+			options = s.options
+		}
+		if notAllowed == nil {
+			notAllowed = s.notAllowed
+		}
+	}
+	if notAllowed == nil {
+		notAllowed = defaultMethodNotAllowed
 	}
-	if r := v.self()[rq.Method]; r != nil {
+
+	if r, ok := v.self()[rq.Method]; ok && r != nil {
 		return r
-	} else {
-		return Handle(http.HandlerFunc(func(rw http.ResponseWriter, rq *http.Request) {
-			rw.WriteHeader(405)
+	}
 
-			MethodNotAllowed(v.Verbs(), rw, rq)
-		}))
+	switch rq.Method {
+	case "HEAD":
+		if r := v["GET"]; r != nil {
+			return r
+		}
+	case "OPTIONS":
+		return HandleFunc(func(rw http.ResponseWriter, rq *http.Request) {
+			verbs := v.Verbs()
+			rw.Header().Set("Allow", strings.Join(verbs, ", "))
+			if options != nil {
+				options(verbs, rw, rq)
+				return
+			}
+			rw.WriteHeader(http.StatusNoContent)
+		})
 	}
+
+	return HandleFunc(func(rw http.ResponseWriter, rq *http.Request) {
+		verbs := v.Verbs()
+		rw.Header().Set("Allow", strings.Join(verbs, ", "))
+
+		//the VerbHandler sets its headers before the status is sent
+		sw := &statusWriter{ResponseWriter: rw, status: http.StatusMethodNotAllowed}
+		defer sw.WriteHeader(sw.status)
+		notAllowed(verbs, sw, rq)
+	})
+}
+
+//statusWriter sends status on the first Write, unless WriteHeader was
+//called first, so that headers set before it are sent.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	wrote  bool
+}
+
+func (s *statusWriter) WriteHeader(status int) {
+	if !s.wrote {
+		s.wrote = true
+		s.ResponseWriter.WriteHeader(status)
+	}
+}
+
+func (s *statusWriter) Write(p []byte) (int, error) {
+	s.WriteHeader(s.status)
+	return s.ResponseWriter.Write(p)
+}
+
+//Type Methods is a Verb with its own VerbHandlers, used in place of those
+//of the nearest RouteHandler when they are non-nil.
+type Methods struct {
+	Verb
+	Options          VerbHandler
+	MethodNotAllowed VerbHandler
+}
+
+func (m Methods) RouteHTTP(rq *http.Request) Router {
+	return m.Verb.route(rq, m.Options, m.MethodNotAllowed)
 }
 
 //A Router that routes based on verbs and provides
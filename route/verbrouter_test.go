@@ -0,0 +1,93 @@
+package route
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVerb(t *testing.T) {
+	h := RouteHandler{
+		Router: Verb{}.Get(HandleFunc(func(rw http.ResponseWriter, rq *http.Request) {
+			rw.Header().Set("X-Method", rq.Method)
+			fmt.Fprint(rw, "body")
+		})).Post(paramsHandler()),
+		NotFound: NotFound,
+		MethodNotAllowed: func(i interface{}, rw http.ResponseWriter, rq *http.Request) {
+			fmt.Fprint(rw, i)
+		},
+	}
+
+	//net/http throws away the body of HEAD responses, but keeps its length
+	s := httptest.NewServer(h)
+	defer s.Close()
+	rs, err := http.Head(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs.Body.Close()
+	if rs.StatusCode != 200 || rs.ContentLength != 4 || rs.Header.Get("X-Method") != "HEAD" {
+		t.Errorf("HEAD: got %d %d %v", rs.StatusCode, rs.ContentLength, rs.Header)
+	}
+
+	const allow = "GET, HEAD, OPTIONS, POST"
+	rw := serve(h, "OPTIONS", "http://example.com/")
+	if rw.Code != http.StatusNoContent || rw.Header().Get("Allow") != allow {
+		t.Errorf("OPTIONS: got %d %v", rw.Code, rw.Header())
+	}
+
+	rw = serve(h, "DELETE", "http://example.com/")
+	if rw.Code != 405 || rw.Header().Get("Allow") != allow || rw.Body.String() != "[GET HEAD OPTIONS POST]" {
+		t.Errorf("DELETE: got %d %q %v", rw.Code, rw.Body, rw.Header())
+	}
+
+	//the default VerbHandler
+	h.MethodNotAllowed = nil
+	rw = serve(h, "DELETE", "http://example.com/")
+	if rw.Code != 405 || rw.Result().Header.Get("Content-Type") != "text/plain;charset=utf8" || rw.Header().Get("Allow") != allow {
+		t.Errorf("DELETE: got %d %q %v", rw.Code, rw.Body, rw.Header())
+	}
+}
+
+func TestVerbNil(t *testing.T) {
+	h := RouteHandler{
+		Router: Verb{"GET": nil, "POST": paramsHandler()},
+	}
+	for _, method := range []string{"GET", "HEAD"} {
+		rw := serve(h, method, "http://example.com/")
+		if rw.Code != 405 || rw.Header().Get("Allow") != "OPTIONS, POST" {
+			t.Errorf("%s: got %d %v", method, rw.Code, rw.Header())
+		}
+	}
+}
+
+func TestVerbHandlers(t *testing.T) {
+	verb := GetOnly(paramsHandler())
+	own := func(i interface{}, rw http.ResponseWriter, rq *http.Request) {
+		fmt.Fprint(rw, "own")
+	}
+	h := RouteHandler{
+		Router: Path{
+			"default": verb,
+			"nested": RouteHandler{
+				Router: verb,
+				MethodNotAllowed: func(i interface{}, rw http.ResponseWriter, rq *http.Request) {
+					fmt.Fprint(rw, "nested")
+				},
+			},
+			"own": Methods{Verb: verb, MethodNotAllowed: own},
+		},
+	}
+
+	for url, expected := range map[string]string{
+		"http://example.com/default": "Method PUT is not allowed, only GET, HEAD, OPTIONS.",
+		"http://example.com/nested":  "nested",
+		"http://example.com/own":     "own",
+	} {
+		rw := serve(h, "PUT", url)
+		if rw.Code != 405 || rw.Body.String() != expected {
+			t.Errorf("%s: expected %q, got %d %q", url, expected, rw.Code, rw.Body.String())
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package route
+
+import (
+	"sort"
+)
+
+type edgeKind uint8
+
+const (
+	//the child is keyed by a path name of a Path
+	pathEdge edgeKind = iota
+	//the child is reached through a Matcher of a TypedPath
+	matcherEdge
+	//the child is keyed by a domain name of a Subdomain
+	domainEdge
+	//the child is keyed by a method of a Verb
+	verbEdge
+	//the child is wrapped by a Router which does not alter the route,
+	//like Named
+	wrapEdge
+	//the child is the tree of a RouteHandler within the tree
+	nestEdge
+	//the child is keyed by a condition on the request, such as the
+	//media types of a Media
+	conditionEdge
+)
+
+const maxDepth = 128
+
+//An edge connects a Router to one of its children.
+type edge struct {
+	kind edgeKind
+	key  string
+	//set for matcherEdges
+	match SegmentMatcher
+	//set for conditionEdges, the condition the child is routed by
+	condition string
+	Router
+}
+
+func sortedKeys(m map[string]Router) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func mapEdges(kind edgeKind, m map[string]Router) (e []edge) {
+	for _, k := range sortedKeys(m) {
+		e = append(e, edge{
+			kind:   kind,
+			key:    k,
+			Router: m[k],
+		})
+	}
+	return
+}
+
+//Function conditionEdges returns the children of m, routed by the value
+//of what.
+func conditionEdges(what string, m map[string]Router) (e []edge) {
+	e = mapEdges(conditionEdge, m)
+	for i := range e {
+		if e[i].key == "" {
+			e[i].condition = what + " (default)"
+		} else {
+			e[i].condition = what + ": " + e[i].key
+		}
+	}
+	return
+}
+
+//Function valueEdges returns the children of a Header, Query or Cookie
+//routing by what, with the condition of each written by format.
+func valueEdges(what string, values map[string]Router, def Router, format func(value string) string) (e []edge) {
+	e = mapEdges(conditionEdge, values)
+	for i := range e {
+		e[i].condition = format(e[i].key)
+	}
+	if def != nil {
+		e = append(e, edge{
+			kind:      conditionEdge,
+			condition: what + " (default)",
+			Router:    def,
+		})
+	}
+	return
+}
+
+//Function children returns the children of the Routers of this package
+//whose children are known without a request, in a stable order.
+//The children of any other Router cannot be known, and are nil.
+func children(r Router) []edge {
+	switch t := r.(type) {
+	case Named:
+		return []edge{{kind: wrapEdge, Router: t.Router}}
+	case *Atomic:
+		return []edge{{kind: wrapEdge, Router: t.Load()}}
+	case *Compiled:
+		return []edge{{kind: wrapEdge, Router: t.router}}
+	case wrapped:
+		return []edge{{kind: wrapEdge, Router: t.Router}}
+	case wrappedPath:
+		return []edge{{kind: wrapEdge, Router: t.Router}}
+	case wrappedDomain:
+		return []edge{{kind: wrapEdge, Router: t.Router}}
+	case wrappedPathDomain:
+		return []edge{{kind: wrapEdge, Router: t.Router}}
+	case Path:
+		return mapEdges(pathEdge, t)
+	case NoExtPath:
+		return mapEdges(pathEdge, t)
+	case FoldPath:
+		return mapEdges(pathEdge, t)
+	case FoldNoExtPath:
+		return mapEdges(pathEdge, t)
+	case TypedPath:
+		e := mapEdges(pathEdge, t.Path)
+		for _, m := range t.Matchers {
+			e = append(e, edge{
+				kind:   matcherEdge,
+				key:    m.Name,
+				match:  m.Match,
+				Router: m.Router,
+			})
+		}
+		return e
+	case Subdomain:
+		return mapEdges(domainEdge, t)
+	case Verb:
+		return mapEdges(verbEdge, t)
+	case Methods:
+		return mapEdges(verbEdge, t.Verb)
+	case RouteHandler:
+		return []edge{{kind: nestEdge, Router: t.Router}}
+	case Media:
+		return conditionEdges("Accept", t)
+	case Header:
+		return valueEdges(t.Name, t.Values, t.Default, func(v string) string {
+			return t.Name + ": " + v
+		})
+	case Query:
+		return valueEdges("?"+t.Name, t.Values, t.Default, func(v string) string {
+			return "?" + t.Name + "=" + v
+		})
+	case Cookie:
+		return valueEdges("Cookie "+t.Name, t.Values, t.Default, func(v string) string {
+			return "Cookie " + t.Name + "=" + v
+		})
+	}
+	return nil
+}
+
+//Function walkable reports whether children knows the children of r.
+func walkable(r Router) bool {
+	switch r.(type) {
+	case Named, Path, NoExtPath, FoldPath, FoldNoExtPath, TypedPath, Subdomain,
+		Verb, Methods, RouteHandler, Media, Header, Query, Cookie, *Atomic,
+		*Compiled, wrapped, wrappedPath, wrappedDomain, wrappedPathDomain:
+		return true
+	}
+	return false
+}
+
+//A hop is one edge on the way from the root of a tree to a Router.
+type hop edge
+
+//Function continuesPath reports whether the PathingRouter at the end of
+//e continues the descent of the path trie its parent takes part in.
+func (e edge) continuesPath() bool {
+	switch e.kind {
+	case pathEdge:
+		return !isWildcard(e.key)
+	case matcherEdge, wrapEdge:
+		return true
+	}
+	return false
+}
+
+//Function continuesDomain is continuesPath for DomainRouters.
+func (e edge) continuesDomain() bool {
+	return e.kind == domainEdge || e.kind == wrapEdge
+}
+
+//A trail is the way from the root of a tree to a Router: the hops that
+//make up the path and domain names it would be reached by, the nearest
+//method and name on the way, and any other conditions it is routed by.
+type trail struct {
+	path, domain []hop
+	method, name string
+	conditions   []string
+}
+
+//Function descend calls visit for r and every Router below it, depth first,
+//with the trail it would be reached by. Names matched by Routers that start
+//a fresh walk of the path or host, such as a Path below a Verb, are dropped
+//from the trail as they would be when routing.
+//
+//If visit returns false the children of that Router are not visited.
+//Trees that contain themselves are walked to a depth of maxDepth.
+func descend(r Router, visit func(r Router, t trail) bool) {
+	var walk func(r Router, t trail, via edge, depth int)
+	walk = func(r Router, t trail, via edge, depth int) {
+		if depth > maxDepth {
+			return
+		}
+
+		if n, ok := r.(Named); ok {
+			t.name = n.Name
+		} else if depth > 0 {
+			if _, ok := r.(PathingRouter); ok && !via.continuesPath() {
+				t.path = nil
+			}
+			if _, ok := r.(DomainRouter); ok && !via.continuesDomain() {
+				t.domain = nil
+			}
+		}
+
+		if !visit(r, t) {
+			return
+		}
+
+		for _, e := range children(r) {
+			c := t
+			switch e.kind {
+			case pathEdge, matcherEdge:
+				c.path = append(t.path[:len(t.path):len(t.path)], hop(e))
+			case domainEdge:
+				c.domain = append(t.domain[:len(t.domain):len(t.domain)], hop(e))
+			case verbEdge:
+				c.method = e.key
+			case conditionEdge:
+				c.conditions = append(t.conditions[:len(t.conditions):len(t.conditions)], e.condition)
+			case wrapEdge:
+				//a wrapper is reached however its parent was
+				walk(e.Router, c, via, depth+1)
+				continue
+			}
+			walk(e.Router, c, e, depth+1)
+		}
+	}
+	walk(r, trail{}, edge{}, 0)
+}